@@ -16,11 +16,17 @@ limitations under the License.
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
+	"strings"
 
+	"github.com/goccy/go-yaml"
 	"github.com/joelee2012/nacosctl/pkg/nacos"
 	"github.com/spf13/cobra"
 )
@@ -32,15 +38,12 @@ var applyCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		if cmdOpts.OutDir != "" {
 			client := NewNacosClient()
-			fi, err := os.Stat(cmdOpts.OutDir)
+			res, err := LoadResources(cmdOpts.OutDir)
 			cobra.CheckErr(err)
-			switch mode := fi.Mode(); {
-			case mode.IsRegular():
-				CreateResourceFromFile(client, cmdOpts.OutDir)
-			case mode.IsDir():
-				CreateResourceFromDir(client, cmdOpts.OutDir)
+			ApplyResources(client, res)
+			if applyOpts.Prune {
+				PruneResources(client, res)
 			}
-
 		}
 	},
 }
@@ -55,35 +58,113 @@ func init() {
 	// configCmd.PersistentFlags().String("foo", "", "A help for foo")
 	applyCmd.Flags().StringVarP(&cmdOpts.OutDir, "filename", "f", "", "The files or dir that contain the configurations")
 	applyCmd.MarkFlagRequired("filename")
+	applyCmd.Flags().BoolVar(&applyOpts.Force, "force", false, "publish even if the configuration changed on the server since status.md5 of the manifest")
+	applyCmd.Flags().BoolVar(&applyOpts.Prune, "prune", false, "delete configurations, role bindings and permissions that are not in the manifests, limited to the namespaces and groups, and the roles and users of the manifests")
+	applyCmd.Flags().StringSliceVar(&applyOpts.PruneAllowlist, "prune-allowlist", nil, "dataId patterns that are never pruned, e.g. 'shared-*.yaml'")
+	applyCmd.Flags().BoolVarP(&applyOpts.Yes, "yes", "y", false, "prune without asking for confirmation")
+	applyCmd.Flags().BoolVar(&applyOpts.UpdatePasswords, "update-passwords", false, "set the password of the users that already exist")
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// configCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
-func CreateResourceFromFile(client *nacos.Client, name string) {
-	ns := new(Namespace)
-	if err := readYamlFile(ns, name); err == nil {
-		cobra.CheckErr(client.CreateOrUpdateNamespace(&nacos.CreateNsOpts{ID: ns.Metadata.ID, Description: ns.Metadata.Description, Name: ns.Metadata.Name}))
-		fmt.Printf("namespace/%s created\n", ns.Metadata.Name)
-		return
+type ApplyOpts struct {
+	Force          bool
+	Prune          bool
+	PruneAllowlist []string
+	Yes            bool
+	// UpdatePasswords sets the password of the users that already exist
+	UpdatePasswords bool
+}
+
+var applyOpts ApplyOpts
+
+// Resources holds the manifests read from a file or a directory, grouped by kind.
+type Resources struct {
+	Namespaces     []*Namespace
+	Configurations []*Configuration
+	Services       []*Service
+	Users          []*User
+	Roles          []*Role
+	Permissions    []*Permission
+}
+
+// LoadResources reads the manifests from name, which is either a single file
+// or a directory walked recursively.
+func LoadResources(name string) (*Resources, error) {
+	res := new(Resources)
+	err := filepath.Walk(name, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		return res.ReadFile(path)
+	})
+	if err != nil {
+		return nil, err
 	}
-	nsNames := ListNamespace(client)
-	c := new(Configuration)
-	cobra.CheckErr(readYamlFile(c, name))
-	if !slices.Contains(nsNames, c.Metadata.Namespace) {
-		cobra.CheckErr(fmt.Errorf("namespace/%s not found", c.Metadata.Namespace))
-	}
-	cobra.CheckErr(client.CreateConfig(&nacos.CreateCfgOpts{
-		DataID:      c.Metadata.DataID,
-		Group:       c.Metadata.Group,
-		NamespaceID: c.Metadata.Namespace,
-		Content:     c.Spec.Content,
-		Type:        c.Spec.Type,
-		Description: c.Spec.Description,
-		Application: c.Spec.Application,
-		Tags:        c.Spec.Tags,
-	}))
-	fmt.Printf("configuration/%s/%s/%s created\n", c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)
+	return res, nil
+}
+
+// ReadFile reads one manifest and appends it to the list matching its kind.
+func (r *Resources) ReadFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var meta struct {
+		Kind string `json:"kind"`
+	}
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	switch meta.Kind {
+	case "Namespace":
+		ns := new(Namespace)
+		if err := readYamlFile(ns, name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r.Namespaces = append(r.Namespaces, ns)
+	case "Configuration":
+		c := new(Configuration)
+		if err := readYamlFile(c, name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		// nacos publishes a beta without ips to every client
+		if beta := c.Spec.Beta; beta != nil && (len(beta.Ips) == 0 || slices.Contains(beta.Ips, "")) {
+			return fmt.Errorf("%s: spec.beta.ips must list the ips of the beta clients", name)
+		}
+		r.Configurations = append(r.Configurations, c)
+	case "Service":
+		svc := new(Service)
+		if err := readYamlFile(svc, name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r.Services = append(r.Services, svc)
+	case "User":
+		u := new(User)
+		if err := readYamlFile(u, name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r.Users = append(r.Users, u)
+	case "Role":
+		role := new(Role)
+		if err := readYamlFile(role, name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r.Roles = append(r.Roles, role)
+	case "Permission":
+		perm := new(Permission)
+		if err := readYamlFile(perm, name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r.Permissions = append(r.Permissions, perm)
+	default:
+		return fmt.Errorf("%s: unsupported kind %q", name, meta.Kind)
+	}
+	return nil
 }
 
 func ListNamespace(client *nacos.Client) []string {
@@ -95,37 +176,21 @@ func ListNamespace(client *nacos.Client) []string {
 	}
 	return nsNames
 }
-func CreateResourceFromDir(naClient *nacos.Client, dir string) {
-	nss := new(NamespaceList)
-	cs := new(ConfigurationList)
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		cobra.CheckErr(err)
-		if !info.IsDir() {
-			var n Namespace
-			if err := readYamlFile(n, path); err == nil {
-				nss.Items = append(nss.Items, n)
-			} else {
-				var c Configuration
-				cobra.CheckErr(readYamlFile(c, path))
-				cs.Items = append(cs.Items, c)
-			}
-		}
-		return nil
-	})
-	cobra.CheckErr(err)
-	nsNames := ListNamespace(naClient)
-	for _, ns := range nss.Items {
-		cobra.CheckErr(naClient.CreateOrUpdateNamespace(&nacos.CreateNsOpts{ID: ns.Metadata.ID, Description: ns.Metadata.Description, Name: ns.Metadata.Name}))
+
+func ApplyResources(client *nacos.Client, res *Resources) {
+	nsNames := ListNamespace(client)
+	for _, ns := range res.Namespaces {
+		cobra.CheckErr(client.CreateOrUpdateNamespace(&nacos.CreateNsOpts{ID: ns.Metadata.ID, Description: ns.Metadata.Description, Name: ns.Metadata.Name}))
 		fmt.Printf("namespace/%s created\n", ns.Metadata.Name)
-		if !slices.Contains(nsNames, ns.Metadata.Name) {
-			nsNames = append(nsNames, ns.Metadata.Name)
+		if !slices.Contains(nsNames, ns.Metadata.ID) {
+			nsNames = append(nsNames, ns.Metadata.ID)
 		}
 	}
-	for _, c := range cs.Items {
+	for _, c := range res.Configurations {
 		if !slices.Contains(nsNames, c.Metadata.Namespace) {
 			cobra.CheckErr(fmt.Errorf("namespace/%s not found", c.Metadata.Namespace))
 		}
-		cobra.CheckErr(naClient.CreateConfig(&nacos.CreateCfgOpts{
+		opts := &nacos.CreateCfgOpts{
 			DataID:      c.Metadata.DataID,
 			Group:       c.Metadata.Group,
 			NamespaceID: c.Metadata.Namespace,
@@ -134,7 +199,350 @@ func CreateResourceFromDir(naClient *nacos.Client, dir string) {
 			Description: c.Spec.Description,
 			Application: c.Spec.Application,
 			Tags:        c.Spec.Tags,
-		}))
+		}
+		if !applyOpts.Force {
+			opts.CasMd5 = c.Status.Md5
+		}
+		err := client.CreateConfig(opts)
+		var conflict *nacos.ConflictError
+		if errors.As(err, &conflict) && isApplied(client, c) {
+			err = nil
+		}
+		cobra.CheckErr(err)
 		fmt.Printf("configuration/%s/%s/%s created\n", c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)
+		if beta := c.Spec.Beta; beta != nil {
+			content := beta.Content
+			if content == "" {
+				content = c.Spec.Content
+			}
+			cobra.CheckErr(client.CreateConfig(&nacos.CreateCfgOpts{
+				DataID:      c.Metadata.DataID,
+				Group:       c.Metadata.Group,
+				NamespaceID: c.Metadata.Namespace,
+				Content:     content,
+				Type:        c.Spec.Type,
+				BetaIps:     strings.Join(beta.Ips, ","),
+			}))
+			fmt.Printf("configuration/%s/%s/%s beta published to %s\n", c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID, strings.Join(beta.Ips, ","))
+		}
+	}
+	for _, svc := range res.Services {
+		if !slices.Contains(nsNames, svc.Metadata.Namespace) {
+			cobra.CheckErr(fmt.Errorf("namespace/%s not found", svc.Metadata.Namespace))
+		}
+		cobra.CheckErr(ApplyService(client, svc))
+	}
+	if len(res.Users) > 0 {
+		users, err := client.ListUser()
+		cobra.CheckErr(err)
+		var names []string
+		for _, u := range users.Items {
+			names = append(names, u.Name)
+		}
+		for _, u := range res.Users {
+			cobra.CheckErr(ApplyUser(client, u, names))
+		}
+	}
+	cobra.CheckErr(ApplyRBAC(client, res))
+}
+
+// ApplyRBAC binds the roles and grants the permissions of the manifests
+// which the server does not have yet.
+func ApplyRBAC(client *nacos.Client, res *Resources) error {
+	if len(res.Roles) > 0 {
+		roles, err := client.ListRole()
+		if err != nil {
+			return err
+		}
+		for _, r := range res.Roles {
+			name := fmt.Sprintf("role/%s/%s", r.Metadata.Name, r.Metadata.Username)
+			if roles.Contains(nacos.Role{Name: r.Metadata.Name, Username: r.Metadata.Username}) {
+				fmt.Printf("%s unchanged\n", name)
+				continue
+			}
+			if err := client.CreateRole(r.Metadata.Name, r.Metadata.Username); err != nil {
+				return err
+			}
+			fmt.Printf("%s created\n", name)
+		}
+	}
+	if len(res.Permissions) > 0 {
+		perms, err := client.ListPermission()
+		if err != nil {
+			return err
+		}
+		for _, p := range res.Permissions {
+			name := fmt.Sprintf("permission/%s/%s/%s", p.Metadata.Role, p.Metadata.Resource, p.Metadata.Action)
+			if perms.Contains(nacos.Permission{Role: p.Metadata.Role, Resource: p.Metadata.Resource, Action: p.Metadata.Action}) {
+				fmt.Printf("%s unchanged\n", name)
+				continue
+			}
+			if err := client.CreatePermission(p.Metadata.Role, p.Metadata.Resource, p.Metadata.Action); err != nil {
+				return err
+			}
+			fmt.Printf("%s created\n", name)
+		}
+	}
+	return nil
+}
+
+// ApplyUser creates the user of the manifest when it is not in users. The
+// password of an existing user is only set again with --update-passwords as
+// the current one can't be read back to compare. The password is read from
+// spec.passwordFrom, a user without it can only be checked for existence.
+func ApplyUser(client *nacos.Client, u *User, users []string) error {
+	name := "user/" + u.Metadata.Name
+	exists := slices.Contains(users, u.Metadata.Name)
+	if exists && (u.Spec.PasswordFrom == nil || !applyOpts.UpdatePasswords) {
+		fmt.Printf("%s unchanged\n", name)
+		return nil
+	}
+	if u.Spec.PasswordFrom == nil {
+		return fmt.Errorf("%s: spec.passwordFrom is required to create the user", name)
+	}
+	password, err := u.Spec.PasswordFrom.Resolve()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if exists {
+		if err := client.UpdateUser(u.Metadata.Name, password); err != nil {
+			return err
+		}
+		fmt.Printf("%s password updated\n", name)
+		return nil
+	}
+	if err := client.CreateUser(u.Metadata.Name, password); err != nil {
+		return err
+	}
+	fmt.Printf("%s created\n", name)
+	return nil
+}
+
+// ApplyService creates the service of the manifest, or updates it when it
+// already exists.
+func ApplyService(client *nacos.Client, svc *Service) error {
+	opts := &nacos.CreateSvcOpts{
+		NamespaceID:      svc.Metadata.Namespace,
+		GroupName:        svc.Metadata.Group,
+		ServiceName:      svc.Metadata.Name,
+		ProtectThreshold: svc.Spec.ProtectThreshold,
+		Metadata:         svc.Spec.Metadata,
+		Selector:         svc.Spec.Selector,
+	}
+	name := fmt.Sprintf("service/%s/%s/%s", opts.NamespaceID, opts.GroupName, opts.ServiceName)
+	live, err := FindService(client, &nacos.GetSvcOpts{NamespaceID: opts.NamespaceID, GroupName: opts.GroupName, ServiceName: opts.ServiceName})
+	if err != nil {
+		return err
+	}
+	if live == nil {
+		if err := client.CreateService(opts); err != nil {
+			return err
+		}
+		fmt.Printf("%s created\n", name)
+		return nil
+	}
+	if err := client.UpdateService(opts); err != nil {
+		return err
+	}
+	fmt.Printf("%s updated\n", name)
+	return nil
+}
+
+// FindService returns the service, or nil if it does not exist. The v1 api
+// answers a missing service with a server error rather than 404, so the
+// existence is checked by listing the services first.
+func FindService(client *nacos.Client, opts *nacos.GetSvcOpts) (*nacos.Service, error) {
+	svcs, err := client.ListService(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range svcs.Items {
+		if svc.GetName() == opts.ServiceName {
+			return client.GetService(opts)
+		}
+	}
+	return nil, nil
+}
+
+// isApplied reports whether the server already has the content of c, so a
+// conflict on status.md5 comes from a previous apply of the same manifest.
+func isApplied(client *nacos.Client, c *Configuration) bool {
+	cfg, err := client.GetConfig(&nacos.GetCfgOpts{DataID: c.Metadata.DataID, Group: c.Metadata.Group, NamespaceID: c.Metadata.Namespace})
+	return err == nil && cfg.Content == c.Spec.Content
+}
+
+// PruneResources deletes the configurations and the rbac bindings on the
+// server that are not declared in res.
+func PruneResources(client *nacos.Client, res *Resources) {
+	pruneConfigs(client, res)
+	pruneRBAC(client, res)
+}
+
+// pruneConfigs deletes the configurations that are not declared in res.
+// Only the namespace and group pairs used by the manifests are considered,
+// and dataIds matching the allowlist are kept.
+func pruneConfigs(client *nacos.Client, res *Resources) {
+	declared := map[string]bool{}
+	scopes := [][2]string{}
+	for _, c := range res.Configurations {
+		declared[configKey(c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)] = true
+		scope := [2]string{c.Metadata.Namespace, c.Metadata.Group}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	var orphans []*nacos.Configuration
+	for _, scope := range scopes {
+		cs, err := client.ListConfigInNs(scope[0], scope[1])
+		cobra.CheckErr(err)
+		orphans = append(orphans, findOrphans(scope[0], cs.Items, declared, applyOpts.PruneAllowlist)...)
+	}
+	if len(orphans) == 0 {
+		return
+	}
+	for _, c := range orphans {
+		fmt.Printf("configuration/%s/%s/%s will be pruned\n", c.GetNamespace(), c.GetGroup(), c.DataID)
+	}
+	if !applyOpts.Yes && !confirm(stdin, os.Stdout, fmt.Sprintf("Delete %d configurations?", len(orphans))) {
+		fmt.Println("prune aborted")
+		return
+	}
+	for _, c := range orphans {
+		cobra.CheckErr(client.DeleteConfig(&nacos.DeleteCfgOpts{DataID: c.DataID, Group: c.GetGroup(), NamespaceID: c.GetNamespace()}))
+		fmt.Printf("configuration/%s/%s/%s pruned\n", c.GetNamespace(), c.GetGroup(), c.DataID)
+	}
+}
+
+// pruneRBAC unbinds the users and revokes the permissions that are not
+// declared in res, within the roles and users the manifests talk about.
+func pruneRBAC(client *nacos.Client, res *Resources) {
+	scope := newRBACScope(res)
+	if len(scope.roles)+len(scope.users) == 0 {
+		return
+	}
+	liveRoles, err := client.ListRole()
+	cobra.CheckErr(err)
+	roles := findOrphanRoles(liveRoles.Items, res.Roles, scope)
+	livePerms, err := client.ListPermission()
+	cobra.CheckErr(err)
+	perms := findOrphanPermissions(livePerms.Items, res.Permissions, scope)
+	if len(roles)+len(perms) == 0 {
+		return
+	}
+	for _, r := range roles {
+		fmt.Printf("role/%s/%s will be pruned\n", r.Name, r.Username)
+	}
+	for _, p := range perms {
+		fmt.Printf("permission/%s/%s/%s will be pruned\n", p.Role, p.Resource, p.Action)
+	}
+	if !applyOpts.Yes && !confirm(stdin, os.Stdout, fmt.Sprintf("Delete %d role bindings and %d permissions?", len(roles), len(perms))) {
+		fmt.Println("prune aborted")
+		return
+	}
+	for _, p := range perms {
+		cobra.CheckErr(client.DeletePermission(p.Role, p.Resource, p.Action))
+		fmt.Printf("permission/%s/%s/%s pruned\n", p.Role, p.Resource, p.Action)
+	}
+	for _, r := range roles {
+		cobra.CheckErr(client.DeleteRole(r.Name, r.Username))
+		fmt.Printf("role/%s/%s pruned\n", r.Name, r.Username)
+	}
+}
+
+// rbacScope holds the roles and the users named by the manifests, only their
+// live bindings and permissions are pruned.
+type rbacScope struct {
+	roles map[string]bool
+	users map[string]bool
+}
+
+func newRBACScope(res *Resources) *rbacScope {
+	scope := &rbacScope{roles: map[string]bool{}, users: map[string]bool{}}
+	for _, r := range res.Roles {
+		scope.roles[r.Metadata.Name] = true
+		scope.users[r.Metadata.Username] = true
+	}
+	for _, p := range res.Permissions {
+		scope.roles[p.Metadata.Role] = true
+	}
+	for _, u := range res.Users {
+		scope.users[u.Metadata.Name] = true
+	}
+	return scope
+}
+
+// findOrphanRoles returns the live bindings of a role or a user of scope that
+// are not declared, so removing the last binding of a role still prunes it.
+// The global admin role is never returned.
+func findOrphanRoles(items []*nacos.Role, declared []*Role, scope *rbacScope) []*nacos.Role {
+	bound := map[nacos.Role]bool{}
+	for _, r := range declared {
+		bound[nacos.Role{Name: r.Metadata.Name, Username: r.Metadata.Username}] = true
+	}
+	var orphans []*nacos.Role
+	for _, r := range items {
+		if (scope.roles[r.Name] || scope.users[r.Username]) && !bound[*r] && r.Name != "ROLE_ADMIN" {
+			orphans = append(orphans, r)
+		}
+	}
+	return orphans
+}
+
+// findOrphanPermissions returns the live permissions of the roles of scope
+// that are not declared.
+func findOrphanPermissions(items []*nacos.Permission, declared []*Permission, scope *rbacScope) []*nacos.Permission {
+	granted := map[nacos.Permission]bool{}
+	for _, p := range declared {
+		granted[nacos.Permission{Role: p.Metadata.Role, Resource: p.Metadata.Resource, Action: p.Metadata.Action}] = true
+	}
+	var orphans []*nacos.Permission
+	for _, p := range items {
+		if scope.roles[p.Role] && !granted[*p] {
+			orphans = append(orphans, p)
+		}
+	}
+	return orphans
+}
+
+func configKey(namespace, group, dataID string) string {
+	return namespace + "/" + group + "/" + dataID
+}
+
+// findOrphans returns the configurations of namespace that are neither
+// declared nor protected by one of the allowlist patterns. The keys use the
+// namespace as queried, the v3 api answers "public" for the empty one.
+func findOrphans(namespace string, items []*nacos.Configuration, declared map[string]bool, allowlist []string) []*nacos.Configuration {
+	var orphans []*nacos.Configuration
+	for _, c := range items {
+		if declared[configKey(namespace, c.GetGroup(), c.DataID)] || isAllowed(c.DataID, allowlist) {
+			continue
+		}
+		orphans = append(orphans, c)
+	}
+	return orphans
+}
+
+func isAllowed(dataID string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		if ok, _ := path.Match(pattern, dataID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stdin is shared by the prompts, a reader per prompt would lose the input
+// it buffered beyond the first answer.
+var stdin = bufio.NewReader(os.Stdin)
+
+// confirm writes prompt to w and reports whether the answer read from r is yes.
+func confirm(r *bufio.Reader, w io.Writer, prompt string) bool {
+	fmt.Fprintf(w, "%s [y/N]: ", prompt)
+	answer, err := r.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(w)
+		return false
 	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadResources(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, NewList(apiVersion, cs, NewConfiguration).WriteToDir(tmpDir))
+	assert.NoError(t, NewList(apiVersion, ns, NewNamespace).WriteToDir(tmpDir))
+	svcs := []*nacos.Service{{Name: "svc1", GroupName: "group1", NamespaceID: "ns1", ProtectThreshold: 0.5}}
+	assert.NoError(t, NewList(apiVersion, svcs, NewService).WriteToDir(tmpDir))
+	assert.NoError(t, NewList(apiVersion, []*nacos.Role{{Name: "dev", Username: "user1"}}, NewRole).WriteToDir(tmpDir))
+	assert.NoError(t, NewList(apiVersion, []*nacos.Permission{{Role: "dev", Resource: "ns1:*:*", Action: "rw"}}, NewPermission).WriteToDir(tmpDir))
+	user := filepath.Join(tmpDir, "users", "user1.yaml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(user), 0750))
+	assert.NoError(t, os.WriteFile(user, []byte("kind: User\nmetadata:\n  username: user1\nspec:\n  passwordFrom:\n    env: USER1_PASSWORD\n"), 0600))
+
+	t.Run("dir", func(t *testing.T) {
+		res, err := LoadResources(tmpDir)
+		if assert.NoError(t, err) {
+			assert.Len(t, res.Namespaces, 1)
+			assert.Len(t, res.Configurations, 2)
+			assert.Equal(t, "ns1", res.Namespaces[0].Metadata.ID)
+			if assert.Len(t, res.Services, 1) {
+				assert.Equal(t, 0.5, res.Services[0].Spec.ProtectThreshold)
+			}
+			if assert.Len(t, res.Users, 1) {
+				assert.Equal(t, &SecretRef{Env: "USER1_PASSWORD"}, res.Users[0].Spec.PasswordFrom)
+			}
+			assert.Len(t, res.Roles, 1)
+			assert.Len(t, res.Permissions, 1)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		res, err := LoadResources(filepath.Join(tmpDir, "ns1", "group1", "data1"))
+		if assert.NoError(t, err) {
+			assert.Len(t, res.Namespaces, 0)
+			assert.Len(t, res.Configurations, 1)
+			assert.Equal(t, "data1", res.Configurations[0].Metadata.DataID)
+		}
+	})
+
+	t.Run("unsupported kind", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "bad.yaml")
+		assert.NoError(t, os.WriteFile(name, []byte("kind: Unknown\n"), 0600))
+		_, err := LoadResources(name)
+		assert.ErrorContains(t, err, `unsupported kind "Unknown"`)
+	})
+
+	t.Run("beta without ips", func(t *testing.T) {
+		for _, beta := range []string{"{data: gray}", "{ips: [], data: gray}", "{ips: [''], data: gray}"} {
+			name := filepath.Join(t.TempDir(), "beta.yaml")
+			assert.NoError(t, os.WriteFile(name, []byte("kind: Configuration\nmetadata:\n  name: data1\nspec:\n  beta: "+beta+"\n"), 0600))
+			_, err := LoadResources(name)
+			assert.ErrorContains(t, err, "spec.beta.ips must list the ips of the beta clients", beta)
+		}
+	})
+
+	t.Run("not exist", func(t *testing.T) {
+		_, err := LoadResources(filepath.Join(tmpDir, "not-exist"))
+		assert.Error(t, err)
+	})
+}
+
+func TestFindOrphans(t *testing.T) {
+	declared := map[string]bool{configKey("ns1", "group1", "data1"): true}
+	items := []*nacos.Configuration{
+		{NamespaceID: "ns1", Group: "group1", DataID: "data1"},
+		{NamespaceID: "ns1", Group: "group1", DataID: "data2"},
+		{NamespaceID: "ns1", Group: "group1", DataID: "shared.yaml"},
+	}
+	orphans := findOrphans("ns1", items, declared, []string{"shared*"})
+	if assert.Len(t, orphans, 1) {
+		assert.Equal(t, "data2", orphans[0].DataID)
+	}
+}
+
+func TestFindOrphansPublicNamespace(t *testing.T) {
+	// manifests of the public namespace leave it empty, v3 lists it as "public"
+	declared := map[string]bool{configKey("", "group1", "data1"): true}
+	items := []*nacos.Configuration{
+		{NamespaceID: "public", GroupName: "group1", DataID: "data1"},
+		{NamespaceID: "public", GroupName: "group1", DataID: "data2"},
+	}
+	orphans := findOrphans("", items, declared, nil)
+	if assert.Len(t, orphans, 1) {
+		assert.Equal(t, "data2", orphans[0].DataID)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.Equal(t, tt.want, confirm(bufio.NewReader(strings.NewReader(tt.input)), &buf, "Delete?"))
+			assert.Contains(t, buf.String(), "Delete? [y/N]")
+		})
+	}
+}
+
+func TestConfirmSharedReader(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("y\nn\ny\n"))
+	var buf bytes.Buffer
+	assert.True(t, confirm(r, &buf, "first?"))
+	assert.False(t, confirm(r, &buf, "second?"))
+	assert.True(t, confirm(r, &buf, "third?"))
+}
+
+func TestFindOrphanRoles(t *testing.T) {
+	res := &Resources{
+		Roles:       []*Role{NewRole(apiVersion, &nacos.Role{Name: "dev", Username: "user1"})},
+		Permissions: []*Permission{NewPermission(apiVersion, &nacos.Permission{Role: "qa", Resource: "ns1:*:*", Action: "r"})},
+	}
+	items := []*nacos.Role{
+		{Name: "dev", Username: "user1"},
+		{Name: "dev", Username: "user2"},
+		{Name: "ops", Username: "user3"},
+		{Name: "qa", Username: "user4"},
+		{Name: "ROLE_ADMIN", Username: "nacos"},
+	}
+	orphans := findOrphanRoles(items, res.Roles, newRBACScope(res))
+	// the last binding of qa was removed from the manifests
+	assert.Equal(t, []*nacos.Role{items[1], items[3]}, orphans)
+}
+
+func TestFindOrphanRolesNoRoleDeclared(t *testing.T) {
+	res := &Resources{Users: []*User{NewUser(apiVersion, &nacos.User{Name: "user1"})}}
+	items := []*nacos.Role{
+		{Name: "dev", Username: "user1"},
+		{Name: "ops", Username: "user2"},
+	}
+	orphans := findOrphanRoles(items, res.Roles, newRBACScope(res))
+	assert.Equal(t, []*nacos.Role{items[0]}, orphans)
+}
+
+func TestFindOrphanPermissions(t *testing.T) {
+	res := &Resources{
+		Roles:       []*Role{NewRole(apiVersion, &nacos.Role{Name: "qa", Username: "user1"})},
+		Permissions: []*Permission{NewPermission(apiVersion, &nacos.Permission{Role: "dev", Resource: "ns1:*:*", Action: "rw"})},
+	}
+	items := []*nacos.Permission{
+		{Role: "dev", Resource: "ns1:*:*", Action: "rw"},
+		{Role: "dev", Resource: "ns2:*:*", Action: "r"},
+		{Role: "ops", Resource: "ns1:*:*", Action: "rw"},
+		{Role: "qa", Resource: "ns1:*:*", Action: "r"},
+	}
+	orphans := findOrphanPermissions(items, res.Permissions, newRBACScope(res))
+	assert.Equal(t, []*nacos.Permission{items[1], items[3]}, orphans)
+}
+
+func TestApplyUserExisting(t *testing.T) {
+	u := NewUser(apiVersion, &nacos.User{Name: "user1"})
+	u.Spec.PasswordFrom = &SecretRef{Env: "NACOSCTL_TEST_UNSET"}
+	// the password is neither read nor sent without --update-passwords
+	assert.NoError(t, ApplyUser(nil, u, []string{"user1"}))
+
+	applyOpts.UpdatePasswords = true
+	defer func() { applyOpts.UpdatePasswords = false }()
+	assert.ErrorContains(t, ApplyUser(nil, u, []string{"user1"}), "NACOSCTL_TEST_UNSET is not set")
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// The nacos console exports configurations as a zip archive, every
+// configuration is stored as "{group}/{dataId}" next to a metadata file.
+const (
+	// metadataFile is written by the current console export and holds the
+	// type, description and application of every configuration
+	metadataFile = ".metadata.yml"
+	// legacyMetadataFile is written by the old console export and holds
+	// only "{group}.{dataId}.app=application" lines
+	legacyMetadataFile = ".meta.yml"
+)
+
+type archiveMetadata struct {
+	Metadata []archiveItem `json:"metadata"`
+}
+
+type archiveItem struct {
+	DataID      string `json:"dataId"`
+	Group       string `json:"group"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"desc,omitempty"`
+	Application string `json:"appName,omitempty"`
+}
+
+// WriteArchive writes the configurations to w in the nacos console format.
+func WriteArchive(w io.Writer, cs []*Configuration) error {
+	zw := zip.NewWriter(w)
+	meta := archiveMetadata{}
+	for _, c := range cs {
+		f, err := zw.Create(c.Metadata.Group + "/" + c.Metadata.DataID)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, c.Spec.Content); err != nil {
+			return err
+		}
+		meta.Metadata = append(meta.Metadata, archiveItem{
+			DataID:      c.Metadata.DataID,
+			Group:       c.Metadata.Group,
+			Type:        c.Spec.Type,
+			Description: c.Spec.Description,
+			Application: c.Spec.Application,
+		})
+	}
+	f, err := zw.Create(metadataFile)
+	if err != nil {
+		return err
+	}
+	if err := yaml.NewEncoder(f).Encode(meta); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadArchive reads a nacos console archive, both the current and the
+// legacy metadata formats are supported. The configurations are returned in
+// the given namespace as the archive does not record it.
+func ReadArchive(r io.ReaderAt, size int64, namespace string) ([]*Configuration, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	contents := map[string]string{}
+	var names []string
+	var meta *archiveMetadata
+	legacyApps := map[string]string{}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		switch f.Name {
+		case metadataFile:
+			meta = new(archiveMetadata)
+			if err := yaml.Unmarshal(data, meta); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+		case legacyMetadataFile:
+			legacyApps = parseLegacyMetadata(data)
+		default:
+			if strings.Count(f.Name, "/") != 1 {
+				return nil, fmt.Errorf("invalid archive entry %q, must be {group}/{dataId}", f.Name)
+			}
+			contents[f.Name] = string(data)
+			names = append(names, f.Name)
+		}
+	}
+	var cs []*Configuration
+	if meta != nil {
+		for _, it := range meta.Metadata {
+			content, ok := contents[it.Group+"/"+it.DataID]
+			if !ok {
+				return nil, fmt.Errorf("no content for %s/%s in archive", it.Group, it.DataID)
+			}
+			c := newArchiveConfiguration(namespace, it.Group, it.DataID, content)
+			c.Spec.Type = it.Type
+			c.Spec.Description = it.Description
+			c.Spec.Application = it.Application
+			cs = append(cs, c)
+		}
+		return cs, nil
+	}
+	for _, name := range names {
+		group, dataID, _ := strings.Cut(name, "/")
+		c := newArchiveConfiguration(namespace, group, dataID, contents[name])
+		c.Spec.Type = typeFromDataID(dataID)
+		c.Spec.Application = legacyApps[legacyMetadataKey(group, dataID)]
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+func newArchiveConfiguration(namespace, group, dataID, content string) *Configuration {
+	c := new(Configuration)
+	c.Kind = "Configuration"
+	c.Metadata.Namespace = namespace
+	c.Metadata.Group = group
+	c.Metadata.DataID = dataID
+	c.Spec.Content = content
+	return c
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// legacyMetadataKey returns the key of the application of a configuration in
+// the legacy metadata, the last dot of the dataId is replaced by "~".
+func legacyMetadataKey(group, dataID string) string {
+	if i := strings.LastIndex(dataID, "."); i >= 0 {
+		dataID = dataID[:i] + "~" + dataID[i+1:]
+	}
+	return group + "." + dataID + ".app"
+}
+
+func parseLegacyMetadata(data []byte) map[string]string {
+	apps := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		if k, v, ok := strings.Cut(strings.TrimSpace(sc.Text()), "="); ok {
+			apps[k] = v
+		}
+	}
+	return apps
+}
+
+// typeFromDataID guesses the type of a configuration from the extension of
+// its dataId, as the nacos console does for legacy archives.
+func typeFromDataID(dataID string) string {
+	switch ext := strings.ToLower(strings.TrimPrefix(path.Ext(dataID), ".")); ext {
+	case "yaml", "yml":
+		return "yaml"
+	case "properties", "json", "xml", "html", "toml":
+		return ext
+	}
+	return "text"
+}
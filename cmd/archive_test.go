@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	cl := NewList(apiVersion, cs, NewConfiguration)
+	items := []*Configuration{&cl.Items[0], &cl.Items[1]}
+	items[0].Spec.Content = "a=1"
+	items[0].Spec.Description = "desc"
+	assert.NoError(t, WriteArchive(&buf, items))
+
+	got, err := ReadArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "target")
+	if assert.NoError(t, err) && assert.Len(t, got, 2) {
+		assert.Equal(t, "target", got[0].Metadata.Namespace)
+		assert.Equal(t, "group1", got[0].Metadata.Group)
+		assert.Equal(t, "data1", got[0].Metadata.DataID)
+		assert.Equal(t, "a=1", got[0].Spec.Content)
+		assert.Equal(t, "type1", got[0].Spec.Type)
+		assert.Equal(t, "desc", got[0].Spec.Description)
+		assert.Equal(t, "app1", got[0].Spec.Application)
+	}
+}
+
+func TestReadLegacyArchive(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"DEFAULT_GROUP/app.yaml": "a: 1",
+		"DEFAULT_GROUP/readme":   "hello",
+		".meta.yml":              "DEFAULT_GROUP.app~yaml.app=demo\n",
+	} {
+		f, err := zw.Create(name)
+		assert.NoError(t, err)
+		f.Write([]byte(content))
+	}
+	assert.NoError(t, zw.Close())
+
+	got, err := ReadArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "")
+	if assert.NoError(t, err) && assert.Len(t, got, 2) {
+		byID := map[string]*Configuration{}
+		for _, c := range got {
+			byID[c.Metadata.DataID] = c
+		}
+		assert.Equal(t, "yaml", byID["app.yaml"].Spec.Type)
+		assert.Equal(t, "demo", byID["app.yaml"].Spec.Application)
+		assert.Equal(t, "text", byID["readme"].Spec.Type)
+		assert.Equal(t, "hello", byID["readme"].Spec.Content)
+	}
+}
+
+func TestReadArchiveInvalidEntry(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, _ := zw.Create("no-group")
+	f.Write([]byte("x"))
+	assert.NoError(t, zw.Close())
+	_, err := ReadArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "")
+	assert.ErrorContains(t, err, "invalid archive entry")
+}
+
+func TestReport(t *testing.T) {
+	var r Report
+	r.Add("configuration/ns/group/data", "created")
+	var buf bytes.Buffer
+	r.ToTable(&buf)
+	assert.Contains(t, buf.String(), "RESULT")
+	assert.Contains(t, buf.String(), "created")
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect authorization",
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// authCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// authCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
+
+// checkAdminErr ends the command with err, telling why the server refused
+// the request: only admins can read the users, roles and permissions.
+func checkAdminErr(err error) {
+	if nacos.IsUnauthorized(err) {
+		err = fmt.Errorf("%w\nreading users, roles and permissions needs an admin user", err)
+	}
+	cobra.CheckErr(err)
+}
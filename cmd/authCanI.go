@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+const globalAdminRole = "ROLE_ADMIN"
+
+var canIAs string
+
+// authCanICmd represents the auth can-i command
+var authCanICmd = &cobra.Command{
+	Use:   "can-i r|w namespace:group:dataId",
+	Short: "Check whether a user is allowed an action on a resource",
+	Long: `Check whether a user is allowed an action on a resource, the way the nacos
+server does, and tell which role and permission grant it.
+
+The resource is namespace:group:dataId for a configuration. A third part with
+a "/", e.g. naming/serviceName, is used as is. Exits with status 1 when the
+action is not allowed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !AuthCanI(args[0], args[1]) {
+			os.Exit(1)
+		}
+	},
+	Args: cobra.ExactArgs(2),
+}
+
+func init() {
+	authCmd.AddCommand(authCanICmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// authCanICmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	authCanICmd.Flags().StringVar(&canIAs, "as", "", "user to check, the user of the current context by default")
+}
+
+func AuthCanI(action, resource string) bool {
+	if action != "r" && action != "w" {
+		cobra.CheckErr(fmt.Errorf("invalid action %q, must be r or w", action))
+	}
+	resource, err := joinResource(resource)
+	cobra.CheckErr(err)
+	client := NewNacosClient()
+	user := canIAs
+	if user == "" {
+		user = client.User
+	}
+	roles, err := client.ListRole()
+	checkAdminErr(err)
+	perms, err := client.ListPermission()
+	checkAdminErr(err)
+	grant, ok := CanI(user, action, resource, roles.Items, perms.Items)
+	switch {
+	case !ok:
+		fmt.Printf("no, no role of user %s grants %s on %s\n", user, action, resource)
+	case grant.Permission == nil:
+		fmt.Printf("yes, user %s has role %s\n", user, grant.Role)
+	default:
+		fmt.Printf("yes, role %s has permission %s on %s\n", grant.Role, grant.Permission.Action, grant.Permission.Resource)
+	}
+	return ok
+}
+
+// Grant is what allows an action, Permission is nil for the global admin role.
+type Grant struct {
+	Role       string
+	Permission *nacos.Permission
+}
+
+// CanI reports whether user may do action on resource, with the first grant
+// that allows it. Like the nacos server, the global admin role allows
+// everything, otherwise "*" in the resource of a permission matches anything
+// and the rest of it is a regular expression.
+func CanI(user, action, resource string, roles []*nacos.Role, perms []*nacos.Permission) (*Grant, bool) {
+	var userRoles []string
+	for _, r := range roles {
+		if r.Username != user {
+			continue
+		}
+		if r.Name == globalAdminRole {
+			return &Grant{Role: r.Name}, true
+		}
+		userRoles = append(userRoles, r.Name)
+	}
+	for _, role := range userRoles {
+		for _, p := range perms {
+			if p.Role == role && strings.Contains(p.Action, action) && matchResource(p.Resource, resource) {
+				return &Grant{Role: role, Permission: p}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func matchResource(pattern, resource string) bool {
+	re, err := regexp.Compile("^(?:" + strings.ReplaceAll(pattern, "*", ".*") + ")$")
+	return err == nil && re.MatchString(resource)
+}
+
+// joinResource turns namespace:group:dataId into the resource name nacos
+// matches permissions against, namespace:group:config/dataId.
+func joinResource(resource string) (string, error) {
+	parts := strings.SplitN(resource, ":", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid resource %q, must be namespace:group:dataId", resource)
+	}
+	if !strings.Contains(parts[2], "/") {
+		parts[2] = "config/" + parts[2]
+	}
+	return strings.Join(parts, ":"), nil
+}
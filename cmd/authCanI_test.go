@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanI(t *testing.T) {
+	roles := []*nacos.Role{
+		{Name: "ROLE_ADMIN", Username: "nacos"},
+		{Name: "dev", Username: "user1"},
+		{Name: "ops", Username: "user1"},
+		{Name: "ops", Username: "user2"},
+	}
+	perms := []*nacos.Permission{
+		{Role: "dev", Resource: "dev:*:*", Action: "rw"},
+		{Role: "ops", Resource: "prod:DEFAULT_GROUP:config/app.*", Action: "r"},
+	}
+	tests := []struct {
+		user     string
+		action   string
+		resource string
+		want     bool
+		role     string
+	}{
+		{"nacos", "w", "prod:DEFAULT_GROUP:config/app.yaml", true, "ROLE_ADMIN"},
+		{"user1", "w", "dev:DEFAULT_GROUP:config/app.yaml", true, "dev"},
+		{"user1", "r", "prod:DEFAULT_GROUP:config/app.yaml", true, "ops"},
+		{"user1", "w", "prod:DEFAULT_GROUP:config/app.yaml", false, ""},
+		{"user2", "r", "prod:OTHER_GROUP:config/app.yaml", false, ""},
+		{"user3", "r", "dev:DEFAULT_GROUP:config/app.yaml", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user+" "+tt.action+" "+tt.resource, func(t *testing.T) {
+			grant, ok := CanI(tt.user, tt.action, tt.resource, roles, perms)
+			assert.Equal(t, tt.want, ok)
+			if ok {
+				assert.Equal(t, tt.role, grant.Role)
+			}
+		})
+	}
+}
+
+func TestJoinResource(t *testing.T) {
+	tests := []struct {
+		resource string
+		want     string
+		err      bool
+	}{
+		{"ns1:DEFAULT_GROUP:app.yaml", "ns1:DEFAULT_GROUP:config/app.yaml", false},
+		{"ns1:DEFAULT_GROUP:naming/svc1", "ns1:DEFAULT_GROUP:naming/svc1", false},
+		{"ns1:app.yaml", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			got, err := joinResource(tt.resource)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
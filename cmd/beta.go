@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// betaCmd represents the beta command
+var betaCmd = &cobra.Command{
+	Use:   "beta",
+	Short: "Manage beta (gray) releases of configurations",
+	Long: `Manage beta (gray) releases of configurations.
+
+A beta release is published with "nctl create cs --beta-ips" or a spec.beta
+block in a manifest, its content is sent only to the listed client ips until
+it is promoted or stopped.`,
+}
+
+func init() {
+	rootCmd.AddCommand(betaCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	betaCmd.PersistentFlags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	betaCmd.PersistentFlags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// betaCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// betaGetCmd represents the beta get command
+var betaGetCmd = &cobra.Command{
+	Use:   "get [flags] name",
+	Short: "Display the beta release of one configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		beta, err := client.GetBetaConfig(&nacos.GetCfgOpts{DataID: args[0], Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID})
+		cobra.CheckErr(err)
+		c := NewBetaConfiguration(client.APIVersion, beta)
+		if betaOutput == "json" {
+			cobra.CheckErr(toJson(c, os.Stdout))
+			return
+		}
+		cobra.CheckErr(toYaml(c, os.Stdout))
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var betaOutput string
+
+func init() {
+	betaCmd.AddCommand(betaGetCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// betaGetCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	betaGetCmd.Flags().StringVarP(&betaOutput, "output", "o", "yaml", "Output format[json, yaml]")
+}
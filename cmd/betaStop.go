@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// betaStopCmd represents the beta stop command
+var betaStopCmd = &cobra.Command{
+	Use:   "stop [flags] name",
+	Short: "Stop the beta release of one or many configurations",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		for _, dataID := range args {
+			cobra.CheckErr(client.StopBetaConfig(&nacos.GetCfgOpts{DataID: dataID, Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID}))
+			fmt.Printf("configuration/%s/%s/%s beta stopped\n", cmdOpts.NamespaceID, cmdOpts.Group, dataID)
+		}
+	},
+	Args: cobra.MinimumNArgs(1),
+}
+
+func init() {
+	betaCmd.AddCommand(betaStopCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// betaStopCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// betaStopCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
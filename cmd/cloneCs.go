@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// cloneCsCmd represents the clone cs command
+var cloneCsCmd = &cobra.Command{
+	Use:     "cs [flags]",
+	Aliases: []string{"configuration"},
+	Short:   "Copy configurations from one namespace to another",
+	Run: func(cmd *cobra.Command, args []string) {
+		CloneCs()
+	},
+}
+
+type CloneOpts struct {
+	From       string
+	To         string
+	Group      string
+	DataIDs    []string
+	Policy     string
+	ServerSide bool
+}
+
+var cloneOpts CloneOpts
+
+func init() {
+	cloneCmd.AddCommand(cloneCsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// cloneCsCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	cloneCsCmd.Flags().StringVar(&cloneOpts.From, "from-namespace", "", "namespace id to copy from")
+	cloneCsCmd.Flags().StringVar(&cloneOpts.To, "to-namespace", "", "namespace id to copy to")
+	cloneCsCmd.MarkFlagRequired("to-namespace")
+	cloneCsCmd.Flags().StringVarP(&cloneOpts.Group, "group", "g", "", "only copy configurations of this group, all groups if empty")
+	cloneCsCmd.Flags().StringSliceVar(&cloneOpts.DataIDs, "data-id", nil, "only copy configurations whose dataId matches one of these patterns, e.g. 'app-*.yaml'")
+	cloneCsCmd.Flags().StringVar(&cloneOpts.Policy, "policy", "abort", "what to do with existing configurations[abort, skip, overwrite]")
+	cloneCsCmd.Flags().BoolVar(&cloneOpts.ServerSide, "server-side", false, "let the server copy the configurations with its clone api")
+}
+
+func CloneCs() {
+	policy, err := nacos.ParseConflictPolicy(cloneOpts.Policy)
+	cobra.CheckErr(err)
+	if cloneOpts.From == cloneOpts.To {
+		cobra.CheckErr("--from-namespace and --to-namespace must be different")
+	}
+	client := NewNacosClient()
+	if !slices.Contains(ListNamespace(client), cloneOpts.To) {
+		cobra.CheckErr(fmt.Errorf("namespace/%s not found", cloneOpts.To))
+	}
+	all, err := client.ListConfigInNs(cloneOpts.From, cloneOpts.Group)
+	cobra.CheckErr(err)
+	var items []*nacos.Configuration
+	for _, c := range all.Items {
+		if len(cloneOpts.DataIDs) == 0 || isAllowed(c.DataID, cloneOpts.DataIDs) {
+			items = append(items, c)
+		}
+	}
+	var report Report
+	if cloneOpts.ServerSide {
+		report, err = CloneCsOnServer(client, items, policy)
+	} else {
+		var cs []*Configuration
+		for _, c := range items {
+			c, err := GetContent(client, c)
+			cobra.CheckErr(err)
+			cfg := NewConfiguration(client.APIVersion, c)
+			cfg.Metadata.Namespace = cloneOpts.To
+			cs = append(cs, cfg)
+		}
+		report, err = PublishConfigs(client, cs, policy)
+	}
+	report.ToTable(os.Stdout)
+	cobra.CheckErr(err)
+}
+
+// CloneCsOnServer copies the configurations with the clone api of the
+// server and reports the outcome of each of them.
+func CloneCsOnServer(client *nacos.Client, items []*nacos.Configuration, policy nacos.ConflictPolicy) (Report, error) {
+	var report Report
+	existing, err := client.ListConfigInNs(cloneOpts.To, "")
+	if err != nil {
+		return report, err
+	}
+	result, err := client.CloneConfig(&nacos.CloneCfgOpts{NamespaceID: cloneOpts.To, Policy: policy, Items: items})
+	if err != nil {
+		return report, err
+	}
+	keys := func(cs []*nacos.Configuration) map[string]bool {
+		m := map[string]bool{}
+		for _, c := range cs {
+			m[c.GetGroup()+"/"+c.DataID] = true
+		}
+		return m
+	}
+	exists, skipped, failed := keys(existing.Items), keys(result.SkipData), keys(result.FailData)
+	for _, c := range items {
+		key := c.GetGroup() + "/" + c.DataID
+		action := "created"
+		switch {
+		case failed[key]:
+			action = "failed"
+		case skipped[key]:
+			action = "skipped"
+		case exists[key]:
+			action = "overwritten"
+		}
+		report.Add(fmt.Sprintf("configuration/%s/%s/%s", cloneOpts.To, c.GetGroup(), c.DataID), action)
+	}
+	return report, nil
+}
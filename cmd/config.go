@@ -16,9 +16,19 @@ limitations under the License.
 package cmd
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/joelee2012/nacosctl/pkg/nacos"
 	"github.com/spf13/cobra"
 )
 
@@ -49,9 +59,97 @@ type CLIConfig struct {
 }
 
 type Server struct {
-	Password string `json:"password"`
-	URL      string `json:"url"`
-	User     string `json:"user"`
+	Password string `json:"password,omitempty"`
+	URL      string `json:"url,omitempty"`
+	// URLs are the nodes of a cluster, used instead of URL
+	URLs []string `json:"urls,omitempty"`
+	User string   `json:"user"`
+	// PasswordFrom reads the password from an environment variable or a file
+	PasswordFrom *SecretRef `json:"passwordFrom,omitempty"`
+	// PasswordCommand is run by the shell, its output is the password
+	PasswordCommand string `json:"passwordCommand,omitempty"`
+	// Timeout limits the time of a request, no limit when 0
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// CAFile is a pem file of certificates trusted in addition to the system ones
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile and KeyFile are the pem files of the client certificate
+	CertFile           string `json:"certFile,omitempty"`
+	KeyFile            string `json:"keyFile,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+	// Proxy is the url of the proxy, the one of the environment when empty
+	Proxy string `json:"proxy,omitempty"`
+	// Retry is the retry policy of failed requests, nacos.DefaultRetryPolicy when nil
+	Retry *nacos.RetryPolicy `json:"retry,omitempty"`
+}
+
+// Addresses returns the urls of the nodes of the server.
+func (s *Server) Addresses() []string {
+	if len(s.URLs) > 0 {
+		return s.URLs
+	}
+	return []string{s.URL}
+}
+
+// HTTPClient returns a client sending requests with the timeout, tls and
+// proxy settings of the server.
+func (s *Server) HTTPClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := &tls.Config{InsecureSkipVerify: s.InsecureSkipVerify}
+	if s.CAFile != "" {
+		pem, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", s.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if s.CertFile != "" || s.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	if s.Proxy != "" {
+		proxy, err := url.Parse(s.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return &http.Client{Transport: transport, Timeout: s.Timeout}, nil
+}
+
+// ResolvePassword returns the password from the first of Password,
+// PasswordFrom and PasswordCommand which is set, empty if none is.
+func (s *Server) ResolvePassword() (string, error) {
+	switch {
+	case s.Password != "":
+		return s.Password, nil
+	case s.PasswordFrom != nil:
+		return s.PasswordFrom.Resolve()
+	case s.PasswordCommand != "":
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", s.PasswordCommand)
+		} else {
+			cmd = exec.Command("sh", "-c", s.PasswordCommand)
+		}
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("password command: %w", err)
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	}
+	return "", nil
 }
 
 func (c *CLIConfig) ReadFile(name string) error {
@@ -16,14 +16,37 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // configAddCmd represents the configAdd command
 var configAddCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a new nacos server",
+	Long: `Add a new nacos server.
+
+The password is read from --password-env, --password-file or
+--password-command when the server is used. Without any of them nor
+--password, it is prompted for when the standard input is a terminal.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(server.URLs) == 1 {
+			server.URL, server.URLs = server.URLs[0], nil
+		}
+		if serverPassword.Env != "" || serverPassword.File != "" {
+			server.PasswordFrom = &serverPassword
+		}
+		if server.Password == "" && server.PasswordFrom == nil && server.PasswordCommand == "" && term.IsTerminal(int(os.Stdin.Fd())) {
+			password, err := promptPassword(os.Stdin, os.Stderr, "Password: ")
+			if err != nil {
+				return err
+			}
+			server.Password = password
+		}
 		cliConfig.AddServer(args[0], server)
 		return cliConfig.WriteFile(cmdOpts.ConfigFile)
 	},
@@ -31,6 +54,7 @@ var configAddCmd = &cobra.Command{
 }
 
 var server = &Server{}
+var serverPassword SecretRef
 
 func init() {
 	configCmd.AddCommand(configAddCmd)
@@ -44,10 +68,28 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// configAddCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	configAddCmd.Flags().StringVar(&server.URL, "url", "", "the nacos url")
+	configAddCmd.Flags().StringSliceVar(&server.URLs, "url", nil, "the nacos url, repeated for the nodes of a cluster")
 	configAddCmd.MarkFlagRequired("url")
 	configAddCmd.Flags().StringVarP(&server.User, "user", "u", "", "nacos user")
 	configAddCmd.MarkFlagRequired("user")
-	configAddCmd.Flags().StringVarP(&server.Password, "password", "p", "", "nacos password")
-	configAddCmd.MarkFlagRequired("password")
+	configAddCmd.Flags().StringVarP(&server.Password, "password", "p", "", "nacos password, saved in clear text and kept in the shell history")
+	configAddCmd.Flags().StringVar(&serverPassword.Env, "password-env", "", "environment variable holding the password")
+	configAddCmd.Flags().StringVar(&serverPassword.File, "password-file", "", "file holding the password")
+	configAddCmd.Flags().StringVar(&server.PasswordCommand, "password-command", "", "command printing the password, e.g. 'pass show nacos'")
+	configAddCmd.MarkFlagsMutuallyExclusive("password", "password-env", "password-file", "password-command")
+	configAddCmd.Flags().DurationVar(&server.Timeout, "timeout", 0, "timeout of a request, e.g. 30s, no timeout when 0")
+	configAddCmd.Flags().StringVar(&server.CAFile, "ca-file", "", "pem file of the certificate authorities to trust")
+	configAddCmd.Flags().StringVar(&server.CertFile, "cert-file", "", "pem file of the client certificate")
+	configAddCmd.Flags().StringVar(&server.KeyFile, "key-file", "", "pem file of the client certificate key")
+	configAddCmd.MarkFlagsRequiredTogether("cert-file", "key-file")
+	configAddCmd.Flags().BoolVar(&server.InsecureSkipVerify, "insecure-skip-verify", false, "do not verify the certificate of the server")
+	configAddCmd.Flags().StringVar(&server.Proxy, "proxy", "", "url of the proxy, the one of HTTPS_PROXY or HTTP_PROXY by default")
+}
+
+// promptPassword asks for a password on the terminal r without echoing it.
+func promptPassword(r *os.File, w io.Writer, prompt string) (string, error) {
+	fmt.Fprint(w, prompt)
+	password, err := term.ReadPassword(int(r.Fd()))
+	fmt.Fprintln(w)
+	return string(password), err
 }
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -134,3 +140,81 @@ func TestToYaml(t *testing.T) {
 	_, err := config.ToYaml()
 	assert.NoError(t, err)
 }
+
+func TestTokenPath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := &Server{URL: "http://127.0.0.1:8848/nacos", User: "nacos"}
+	p1, err := tokenPath("test", server)
+	assert.NoError(t, err)
+	p2, _ := tokenPath("test", &Server{URL: server.URL, User: "other"})
+	p3, _ := tokenPath("test", &Server{URL: server.URL, User: "nacos", Password: "changed"})
+	assert.NotEqual(t, p1, p2)
+	assert.Equal(t, p1, p3)
+	assert.Contains(t, p1, filepath.Join("nacosctl", "tokens", "test-"))
+}
+
+func TestResolvePassword(t *testing.T) {
+	t.Setenv("NACOS_TEST_PASSWORD", "from-env")
+	file := filepath.Join(t.TempDir(), "password")
+	assert.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0600))
+	tests := []struct {
+		name   string
+		server *Server
+		want   string
+	}{
+		{"Plain", &Server{Password: "plain", PasswordCommand: "echo other"}, "plain"},
+		{"Env", &Server{PasswordFrom: &SecretRef{Env: "NACOS_TEST_PASSWORD"}}, "from-env"},
+		{"File", &Server{PasswordFrom: &SecretRef{File: file}}, "from-file"},
+		{"Command", &Server{PasswordCommand: "echo from-command"}, "from-command"},
+		{"None", &Server{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.server.ResolvePassword()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	_, err := (&Server{PasswordCommand: "exit 1"}).ResolvePassword()
+	assert.Error(t, err)
+}
+
+func TestServerHTTPClient(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	// the handshake rejected below is logged otherwise
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.StartTLS()
+	defer ts.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caFile, cert, 0600))
+
+	hc, err := (&Server{CAFile: caFile, Timeout: 5 * time.Second}).HTTPClient()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5*time.Second, hc.Timeout)
+		resp, err := hc.Get(ts.URL)
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+		}
+	}
+
+	hc, _ = (&Server{}).HTTPClient()
+	_, err = hc.Get(ts.URL)
+	assert.Error(t, err, "untrusted certificate")
+
+	hc, _ = (&Server{InsecureSkipVerify: true, Proxy: "http://proxy:3128"}).HTTPClient()
+	proxy, err := hc.Transport.(*http.Transport).Proxy(httptest.NewRequest(http.MethodGet, ts.URL, nil))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "proxy:3128", proxy.Host)
+	}
+
+	_, err = (&Server{CAFile: filepath.Join(t.TempDir(), "missing.pem")}).HTTPClient()
+	assert.Error(t, err)
+	_, err = (&Server{CertFile: caFile, KeyFile: caFile}).HTTPClient()
+	assert.Error(t, err)
+}
+
+func TestServerAddresses(t *testing.T) {
+	assert.Equal(t, []string{"url1"}, (&Server{URL: "url1"}).Addresses())
+	assert.Equal(t, []string{"url1", "url2"}, (&Server{URL: "url0", URLs: []string{"url1", "url2"}}).Addresses())
+}
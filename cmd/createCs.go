@@ -31,6 +31,10 @@ var createCsCmd = &cobra.Command{
 		client := NewNacosClient()
 		createOpts.DataID = args[0]
 		cobra.CheckErr(client.CreateConfig(&createOpts))
+		if createOpts.BetaIps != "" {
+			fmt.Printf("configuration/%s/%s/%s beta published to %s\n", createOpts.NamespaceID, createOpts.Group, createOpts.DataID, createOpts.BetaIps)
+			return
+		}
 		fmt.Printf("configuration/%s/%s/%s created\n", createOpts.NamespaceID, createOpts.Group, createOpts.DataID)
 
 	},
@@ -59,5 +63,7 @@ func init() {
 	createCsCmd.Flags().StringVarP(&createOpts.Description, "description", "d", "", "description of configuration")
 	createCsCmd.Flags().StringVarP(&createOpts.Tags, "tags", "T", "", "tags of configuration")
 	createCsCmd.Flags().StringVarP(&createOpts.Application, "application", "a", "", "application of configuration")
+	createCsCmd.Flags().StringVar(&createOpts.CasMd5, "expected-md5", "", "only publish if the configuration on the server has this md5")
+	createCsCmd.Flags().StringVar(&createOpts.BetaIps, "beta-ips", "", "comma separated client ips, publish the content only to them as a beta release")
 
 }
@@ -0,0 +1,56 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// createPermCmd represents the createPermission command
+var createPermCmd = &cobra.Command{
+	Use:     "perm role",
+	Aliases: []string{"permission"},
+	Short:   "Grant one permission to a role",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		cobra.CheckErr(client.CreatePermission(args[0], rbacOpts.Resource, rbacOpts.Action))
+		fmt.Printf("permission/%s/%s/%s created\n", args[0], rbacOpts.Resource, rbacOpts.Action)
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	createCmd.AddCommand(createPermCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// createPermCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	addPermFlags(createPermCmd)
+}
+
+func addPermFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&rbacOpts.Resource, "resource", "r", "", "resource of the permission, e.g. namespaceId:*:*")
+	cmd.MarkFlagRequired("resource")
+	cmd.Flags().StringVarP(&rbacOpts.Action, "action", "a", "", "action of the permission, one of r, w or rw")
+	cmd.MarkFlagRequired("action")
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// RBACOpts holds the flags of the role and permission commands.
+type RBACOpts struct {
+	Username string
+	Resource string
+	Action   string
+}
+
+var rbacOpts RBACOpts
+
+// createRoleCmd represents the createRole command
+var createRoleCmd = &cobra.Command{
+	Use:     "role name",
+	Aliases: []string{"r"},
+	Short:   "Bind one role to a user",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		cobra.CheckErr(client.CreateRole(args[0], rbacOpts.Username))
+		fmt.Printf("role/%s/%s created\n", args[0], rbacOpts.Username)
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	createCmd.AddCommand(createRoleCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// createRoleCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	createRoleCmd.Flags().StringVarP(&rbacOpts.Username, "username", "u", "", "name of the user to bind the role to")
+	createRoleCmd.MarkFlagRequired("username")
+}
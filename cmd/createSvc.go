@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// createSvcCmd represents the createSvc command
+var createSvcCmd = &cobra.Command{
+	Use:     "svc name",
+	Aliases: []string{"service"},
+	Short:   "Create or update one service",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		svcOpts.ServiceName = args[0]
+		svcOpts.NamespaceID = cmdOpts.NamespaceID
+		svcOpts.GroupName = cmdOpts.Group
+		if svcSelector != "" {
+			svcOpts.Selector = &nacos.Selector{Type: "label", Expression: svcSelector}
+		}
+		live, err := FindService(client, &nacos.GetSvcOpts{NamespaceID: svcOpts.NamespaceID, GroupName: svcOpts.GroupName, ServiceName: svcOpts.ServiceName})
+		cobra.CheckErr(err)
+		if live != nil {
+			cobra.CheckErr(client.UpdateService(&svcOpts))
+			fmt.Printf("service/%s updated\n", svcOpts.ServiceName)
+			return
+		}
+		cobra.CheckErr(client.CreateService(&svcOpts))
+		fmt.Printf("service/%s created\n", svcOpts.ServiceName)
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var svcOpts nacos.CreateSvcOpts
+var svcSelector string
+
+func init() {
+	createCmd.AddCommand(createSvcCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// createSvcCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	createSvcCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	createSvcCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	createSvcCmd.Flags().Float64VarP(&svcOpts.ProtectThreshold, "protect-threshold", "t", 0, "protect threshold of service, between 0 and 1")
+	createSvcCmd.Flags().StringToStringVarP(&svcOpts.Metadata, "metadata", "m", nil, "metadata of service, e.g. key1=value1,key2=value2")
+	createSvcCmd.Flags().StringVar(&svcSelector, "selector", "", "label selector expression, e.g. CONSUMER.label.env = PROVIDER.label.env")
+}
@@ -0,0 +1,53 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// deleteRoleCmd represents the deleteRole command
+var deleteRoleCmd = &cobra.Command{
+	Use:     "role name",
+	Aliases: []string{"r"},
+	Short:   "Unbind one role from a user, or delete it from all users",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		cobra.CheckErr(client.DeleteRole(args[0], rbacOpts.Username))
+		if rbacOpts.Username == "" {
+			fmt.Printf("role/%s deleted\n", args[0])
+		} else {
+			fmt.Printf("role/%s/%s deleted\n", args[0], rbacOpts.Username)
+		}
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	deleteCmd.AddCommand(deleteRoleCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// deleteRoleCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	deleteRoleCmd.Flags().StringVarP(&rbacOpts.Username, "username", "u", "", "name of the user to unbind, all users when empty")
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// deleteSvcCmd represents the deleteSvc command
+var deleteSvcCmd = &cobra.Command{
+	Use:     "svc",
+	Aliases: []string{"service"},
+	Short:   "Delete one or many services",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		for _, name := range args {
+			err := client.DeleteService(&nacos.DeleteSvcOpts{
+				NamespaceID: cmdOpts.NamespaceID,
+				GroupName:   cmdOpts.Group,
+				ServiceName: name,
+			})
+			cobra.CheckErr(err)
+			fmt.Printf("service/%s deleted\n", name)
+		}
+	},
+	Args: cobra.MinimumNArgs(1),
+}
+
+func init() {
+	deleteCmd.AddCommand(deleteSvcCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// deleteSvcCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	deleteSvcCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	deleteSvcCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+}
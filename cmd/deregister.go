@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// deregisterCmd represents the deregister command
+var deregisterCmd = &cobra.Command{
+	Use:   "deregister ip:port",
+	Short: "Deregister an instance of a service",
+	Run: func(cmd *cobra.Command, args []string) {
+		Deregister(args[0])
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	rootCmd.AddCommand(deregisterCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// deregisterCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	deregisterCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	deregisterCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	deregisterCmd.Flags().StringVar(&instOpts.Service, "service", "", "name of service")
+	deregisterCmd.MarkFlagRequired("service")
+	deregisterCmd.Flags().StringVarP(&patchInstOpts.Cluster, "cluster", "c", "", "cluster of the instance, required if the address is in several clusters")
+}
+
+func Deregister(addr string) {
+	client := NewNacosClient()
+	insts, err := client.ListInstance(&nacos.ListInstOpts{NamespaceID: cmdOpts.NamespaceID, GroupName: cmdOpts.Group, ServiceName: instOpts.Service})
+	cobra.CheckErr(err)
+	// the listed instance knows whether it is ephemeral, which the server
+	// needs to find it
+	inst, err := findInstance(insts.Items, addr, patchInstOpts.Cluster)
+	cobra.CheckErr(err)
+	cobra.CheckErr(client.DeregisterInstance(inst))
+	fmt.Printf("instance/%s deregistered\n", net.JoinHostPort(inst.IP, fmt.Sprint(inst.Port)))
+}
@@ -0,0 +1,230 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [flags]",
+	Short: "Diff configuration file against nacos",
+	Long: `Show the changes apply would make to nacos.
+
+Exits with status 1 when there are differences.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		res, err := LoadResources(cmdOpts.OutDir)
+		cobra.CheckErr(err)
+		changed, err := DiffResources(client, res, os.Stdout)
+		cobra.CheckErr(err)
+		if changed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// diffCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	diffCmd.Flags().StringVarP(&cmdOpts.OutDir, "filename", "f", "", "The files or dir that contain the configurations")
+	diffCmd.MarkFlagRequired("filename")
+}
+
+// DiffResources compares every manifest in res with the live object on the
+// server and writes the result to w. It reports whether anything differs.
+func DiffResources(client *nacos.Client, res *Resources, w io.Writer) (bool, error) {
+	changed := false
+	for _, ns := range res.Namespaces {
+		local := *ns
+		local.Status = Namespace{}.Status
+		var live *Namespace
+		n, err := client.GetNamespace(ns.Metadata.ID)
+		if err != nil && !nacos.IsNotFound(err) {
+			return changed, err
+		}
+		if n != nil {
+			live = NewNamespace(local.APIVersion, n)
+			live.Status = local.Status
+		}
+		diff, err := diffObject(w, "namespace/"+ns.Metadata.ID, live, &local)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || diff
+	}
+	for _, c := range res.Configurations {
+		local := *c
+		local.Status = Configuration{}.Status
+		var live *Configuration
+		cfg, err := client.GetConfig(&nacos.GetCfgOpts{DataID: c.Metadata.DataID, Group: c.Metadata.Group, NamespaceID: c.Metadata.Namespace})
+		if err != nil && !nacos.IsNotFound(err) {
+			return changed, err
+		}
+		if cfg != nil {
+			live = NewConfiguration(local.APIVersion, cfg)
+			live.Status = local.Status
+		}
+		if live != nil && local.Spec.Beta != nil {
+			beta, err := client.GetBetaConfig(&nacos.GetCfgOpts{DataID: c.Metadata.DataID, Group: c.Metadata.Group, NamespaceID: c.Metadata.Namespace})
+			if err != nil && !nacos.IsNotFound(err) {
+				return changed, err
+			}
+			if beta != nil {
+				live.Spec.Beta = NewBetaConfiguration(local.APIVersion, beta).Spec.Beta
+				if local.Spec.Beta.Content == "" && live.Spec.Beta.Content == local.Spec.Content {
+					live.Spec.Beta.Content = ""
+				}
+			}
+		}
+		name := fmt.Sprintf("configuration/%s/%s/%s", c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)
+		diff, err := diffObject(w, name, live, &local)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || diff
+	}
+	for _, svc := range res.Services {
+		local := *svc
+		local.Status = Service{}.Status
+		var live *Service
+		s, err := FindService(client, &nacos.GetSvcOpts{NamespaceID: svc.Metadata.Namespace, GroupName: svc.Metadata.Group, ServiceName: svc.Metadata.Name})
+		if err != nil {
+			return changed, err
+		}
+		if s != nil {
+			live = NewService(local.APIVersion, s)
+			live.Status = local.Status
+		}
+		name := fmt.Sprintf("service/%s/%s/%s", svc.Metadata.Namespace, svc.Metadata.Group, svc.Metadata.Name)
+		diff, err := diffObject(w, name, live, &local)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || diff
+	}
+	for _, u := range res.Users {
+		// passwords are hashed by the server, only the existence of the
+		// user can be compared
+		local := *u
+		local.Metadata.Password = ""
+		local.Spec = User{}.Spec
+		var live *User
+		user, err := client.GetUser(u.Metadata.Name)
+		if err != nil && !nacos.IsNotFound(err) {
+			return changed, err
+		}
+		if user != nil {
+			live = NewUser(local.APIVersion, user)
+			live.Metadata.Password = ""
+		}
+		diff, err := diffObject(w, "user/"+u.Metadata.Name, live, &local)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || diff
+	}
+	if len(res.Roles) > 0 {
+		roles, err := client.ListRole()
+		if err != nil {
+			return changed, err
+		}
+		for _, r := range res.Roles {
+			var live *Role
+			if roles.Contains(nacos.Role{Name: r.Metadata.Name, Username: r.Metadata.Username}) {
+				live = r
+			}
+			diff, err := diffObject(w, fmt.Sprintf("role/%s/%s", r.Metadata.Name, r.Metadata.Username), live, r)
+			if err != nil {
+				return changed, err
+			}
+			changed = changed || diff
+		}
+	}
+	if len(res.Permissions) > 0 {
+		perms, err := client.ListPermission()
+		if err != nil {
+			return changed, err
+		}
+		for _, p := range res.Permissions {
+			var live *Permission
+			if perms.Contains(nacos.Permission{Role: p.Metadata.Role, Resource: p.Metadata.Resource, Action: p.Metadata.Action}) {
+				live = p
+			}
+			diff, err := diffObject(w, fmt.Sprintf("permission/%s/%s/%s", p.Metadata.Role, p.Metadata.Resource, p.Metadata.Action), live, p)
+			if err != nil {
+				return changed, err
+			}
+			changed = changed || diff
+		}
+	}
+	return changed, nil
+}
+
+// diffObject writes a unified diff between the yaml of live and local,
+// prefixed by a line telling whether the object would be created, changed
+// or left unchanged. A nil live means the object does not exist yet.
+func diffObject[T any](w io.Writer, name string, live, local *T) (bool, error) {
+	var a, b bytes.Buffer
+	if live != nil {
+		if err := toYaml(live, &a); err != nil {
+			return false, err
+		}
+	}
+	if err := toYaml(local, &b); err != nil {
+		return false, err
+	}
+	if a.String() == b.String() {
+		_, err := fmt.Fprintf(w, "%s unchanged\n", name)
+		return false, err
+	}
+	state := "changed"
+	if live == nil {
+		state = "created"
+	}
+	text, err := unifiedDiff(a.String(), b.String(), "live/"+name, "local/"+name)
+	if err != nil {
+		return true, err
+	}
+	_, err = fmt.Fprintf(w, "%s %s\n%s", name, state, text)
+	return true, err
+}
+
+func unifiedDiff(a, b, fromFile, toFile string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
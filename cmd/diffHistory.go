@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// diffHistoryCmd represents the diff history command
+var diffHistoryCmd = &cobra.Command{
+	Use:   "history [flags] name",
+	Short: "Diff two versions of one configuration",
+	Long: `Show the content changes between two history entries of a configuration.
+
+Without --to the entry given by --from is compared with the current content.
+Exits with status 1 when there are differences.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		changed, err := DiffCsHistory(args[0])
+		cobra.CheckErr(err)
+		if changed {
+			os.Exit(1)
+		}
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var diffHistoryOpts struct {
+	From string
+	To   string
+}
+
+func init() {
+	diffCmd.AddCommand(diffHistoryCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// diffHistoryCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	diffHistoryCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	diffHistoryCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	diffHistoryCmd.Flags().StringVar(&diffHistoryOpts.From, "from", "", "history id of the old version")
+	diffHistoryCmd.MarkFlagRequired("from")
+	diffHistoryCmd.Flags().StringVar(&diffHistoryOpts.To, "to", "", "history id of the new version (default is the current content)")
+}
+
+func DiffCsHistory(dataID string) (bool, error) {
+	client := NewNacosClient()
+	opts := &nacos.GetHistoryOpts{ID: diffHistoryOpts.From, DataID: dataID, Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID}
+	from, err := client.GetConfigHistory(opts)
+	if err != nil {
+		return false, err
+	}
+	toName, toContent := "current", ""
+	if diffHistoryOpts.To != "" {
+		opts.ID = diffHistoryOpts.To
+		to, err := client.GetConfigHistory(opts)
+		if err != nil {
+			return false, err
+		}
+		toName, toContent = "history/"+diffHistoryOpts.To, to.Content
+	} else {
+		cfg, err := client.GetConfig(&nacos.GetCfgOpts{DataID: dataID, Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID})
+		if err != nil && !nacos.IsNotFound(err) {
+			return false, err
+		}
+		if cfg != nil {
+			toContent = cfg.Content
+		}
+	}
+	if from.Content == toContent {
+		return false, nil
+	}
+	name := fmt.Sprintf("configuration/%s/%s/%s", cmdOpts.NamespaceID, cmdOpts.Group, dataID)
+	text, err := unifiedDiff(from.Content, toContent, "history/"+diffHistoryOpts.From+"/"+name, toName+"/"+name)
+	if err != nil {
+		return true, err
+	}
+	_, err = fmt.Print(text)
+	return true, err
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffObject(t *testing.T) {
+	live := NewConfiguration(apiVersion, cs[0])
+	changed := *live
+	changed.Spec.Content = "new content"
+	tests := []struct {
+		name    string
+		live    *Configuration
+		want    bool
+		wantOut string
+	}{
+		{"unchanged", live, false, "configuration/x unchanged\n"},
+		{"changed", &changed, true, "configuration/x changed\n"},
+		{"created", nil, true, "configuration/x created\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			diff, err := diffObject(&buf, "configuration/x", tt.live, live)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, diff)
+				assert.Contains(t, buf.String(), tt.wantOut)
+			}
+		})
+	}
+}
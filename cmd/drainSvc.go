@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+var drainOpts struct {
+	Duration time.Duration
+}
+
+// drainSvcCmd represents the drain svc command
+var drainSvcCmd = &cobra.Command{
+	Use:     "svc [flags] name",
+	Aliases: []string{"service"},
+	Short:   "Disable all instances of a service until interrupted, then enable them again",
+	Long: `Disable all enabled instances of a service, wait until the duration elapses
+or the command is interrupted (Ctrl-C), then enable the instances it disabled.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		DrainSvc(cmd.Context(), args[0])
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	drainCmd.AddCommand(drainSvcCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// drainSvcCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	drainSvcCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	drainSvcCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	drainSvcCmd.Flags().DurationVarP(&drainOpts.Duration, "duration", "d", 0, "how long to keep the instances disabled, until interrupted when 0")
+}
+
+func DrainSvc(ctx context.Context, name string) {
+	client := NewNacosClient()
+	insts, err := client.ListInstance(&nacos.ListInstOpts{NamespaceID: cmdOpts.NamespaceID, GroupName: cmdOpts.Group, ServiceName: name})
+	cobra.CheckErr(err)
+	drained, err := SetInstancesEnabled(client, insts.Items, false)
+	// enable again whatever was disabled, even if disabling the rest failed
+	if err == nil {
+		fmt.Printf("service/%s drained, %d instances disabled\n", name, len(drained))
+		if drainOpts.Duration > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, drainOpts.Duration)
+			defer cancel()
+		}
+		<-ctx.Done()
+	}
+	// the instances are enabled again after the interrupt canceled ctx
+	_, restoreErr := SetInstancesEnabled(client.WithContext(context.WithoutCancel(ctx)), drained, true)
+	cobra.CheckErr(err)
+	cobra.CheckErr(restoreErr)
+	fmt.Printf("service/%s restored, %d instances enabled\n", name, len(drained))
+}
+
+// SetInstancesEnabled enables or disables the instances whose state differs
+// from enabled and returns the ones it changed.
+func SetInstancesEnabled(client *nacos.Client, items []*nacos.Instance, enabled bool) ([]*nacos.Instance, error) {
+	var changed []*nacos.Instance
+	for _, inst := range items {
+		if inst.Enabled == enabled {
+			continue
+		}
+		inst.Enabled = enabled
+		if err := client.UpdateInstance(inst); err != nil {
+			inst.Enabled = !enabled
+			return changed, fmt.Errorf("instance %s:%d: %w", inst.IP, inst.Port, err)
+		}
+		changed = append(changed, inst)
+	}
+	return changed, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [flags] [name...]",
+	Short: "Export configurations to a nacos console zip archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		ExportCs(args)
+	},
+}
+
+type ArchiveOpts struct {
+	File   string
+	Group  string
+	Policy string
+}
+
+var archiveOpts ArchiveOpts
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// exportCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	exportCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	exportCmd.Flags().StringVarP(&archiveOpts.Group, "group", "g", "", "group name, all groups if empty")
+	exportCmd.Flags().StringVarP(&archiveOpts.File, "file", "f", "", "the zip file to write")
+	exportCmd.MarkFlagRequired("file")
+}
+
+func ExportCs(args []string) {
+	client := NewNacosClient()
+	cs, err := client.ListConfigInNs(cmdOpts.NamespaceID, archiveOpts.Group)
+	cobra.CheckErr(err)
+	var items []*Configuration
+	for _, c := range cs.Items {
+		if len(args) > 0 && !slices.Contains(args, c.DataID) {
+			continue
+		}
+		c, err := GetContent(client, c)
+		cobra.CheckErr(err)
+		items = append(items, NewConfiguration(client.APIVersion, c))
+	}
+	f, err := os.Create(archiveOpts.File)
+	cobra.CheckErr(err)
+	defer f.Close()
+	cobra.CheckErr(WriteArchive(f, items))
+	fmt.Printf("%d configurations exported to %s\n", len(items), archiveOpts.File)
+}
+
+// GetContent returns cfg with its content, the v3 list api does not return
+// the content of the configurations.
+func GetContent(client *nacos.Client, cfg *nacos.Configuration) (*nacos.Configuration, error) {
+	if cfg.Content != "" {
+		return cfg, nil
+	}
+	return client.GetConfig(&nacos.GetCfgOpts{DataID: cfg.DataID, Group: cfg.GetGroup(), NamespaceID: cfg.GetNamespace()})
+}
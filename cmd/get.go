@@ -16,6 +16,10 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
 	"github.com/spf13/cobra"
 )
 
@@ -39,3 +43,27 @@ func init() {
 	// getCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
 }
+
+// checkNotFound prints that resource does not exist and reports true when
+// err is a not found error, other errors end the command.
+func checkNotFound(err error, resource string) bool {
+	if nacos.IsNotFound(err) {
+		fmt.Fprintf(os.Stderr, "%s not found\n", resource)
+		return true
+	}
+	cobra.CheckErr(err)
+	return false
+}
+
+// printNotFound prints the names which are not in found, it reports whether
+// there is any.
+func printNotFound(kind string, names []string, found func(string) bool) bool {
+	missing := false
+	for _, name := range names {
+		if !found(name) {
+			fmt.Fprintf(os.Stderr, "%s/%s not found\n", kind, name)
+			missing = true
+		}
+	}
+	return missing
+}
@@ -0,0 +1,247 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// getAccessMatrixCmd represents the get access-matrix command
+var getAccessMatrixCmd = &cobra.Command{
+	Use:     "access-matrix",
+	Aliases: []string{"am"},
+	Short:   "Display what every user can do in every namespace",
+	Long: `Display what every user can do in every namespace: r, w or rw.
+
+Global admins, users without roles and permissions on namespaces that do not
+exist are flagged. Output formats are table, csv, json and yaml.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		GetAccessMatrix()
+	},
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	getCmd.AddCommand(getAccessMatrixCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// getAccessMatrixCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// getAccessMatrixCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
+
+func GetAccessMatrix() {
+	client := NewNacosClient()
+	users, err := client.ListUser()
+	checkAdminErr(err)
+	roles, err := client.ListRole()
+	checkAdminErr(err)
+	perms, err := client.ListPermission()
+	checkAdminErr(err)
+	nss, err := client.ListNamespace()
+	cobra.CheckErr(err)
+	matrix := NewAccessMatrix(users.Items, roles.Items, perms.Items, nss.Items)
+	// the token tells whether the user of the context is a global admin even
+	// when it does not get it from a role, e.g. the builtin nacos user
+	if client.Token != nil && client.GlobalAdmin {
+		matrix.SetGlobalAdmin(client.User)
+	}
+	if cmdOpts.Output == "csv" {
+		cobra.CheckErr(matrix.ToCSV(os.Stdout))
+		return
+	}
+	cobra.CheckErr(WriteFormat(matrix, cmdOpts.Output, os.Stdout))
+}
+
+type UserAccess struct {
+	Name        string            `json:"name"`
+	Roles       []string          `json:"roles"`
+	GlobalAdmin bool              `json:"globalAdmin,omitempty"`
+	Access      map[string]string `json:"access"`
+}
+
+// Flags returns the warnings about the user for an auditor.
+func (u *UserAccess) Flags() []string {
+	var flags []string
+	if u.GlobalAdmin {
+		flags = append(flags, "global-admin")
+	}
+	if len(u.Roles) == 0 {
+		flags = append(flags, "no-roles")
+	}
+	return flags
+}
+
+// AccessMatrix is the action, r, w or rw, every user may do in every
+// namespace, as granted by the permissions of its roles.
+type AccessMatrix struct {
+	Namespaces []string      `json:"namespaces"`
+	Users      []*UserAccess `json:"users"`
+	// DanglingPermissions point at namespaces that do not exist
+	DanglingPermissions []*nacos.Permission `json:"danglingPermissions,omitempty"`
+}
+
+func NewAccessMatrix(users []*nacos.User, roles []*nacos.Role, perms []*nacos.Permission, nss []*nacos.Namespace) *AccessMatrix {
+	m := new(AccessMatrix)
+	// permissions are matched against the id the server uses, which is
+	// empty for the public namespace on v1 and "public" on v3
+	var ids []string
+	for _, ns := range nss {
+		ids = append(ids, ns.ID)
+		m.Namespaces = append(m.Namespaces, namespaceLabel(ns.ID))
+	}
+	byName := map[string]*UserAccess{}
+	add := func(name string) *UserAccess {
+		if u, ok := byName[name]; ok {
+			return u
+		}
+		u := &UserAccess{Name: name, Roles: []string{}, Access: map[string]string{}}
+		byName[name] = u
+		m.Users = append(m.Users, u)
+		return u
+	}
+	for _, u := range users {
+		add(u.Name)
+	}
+	// users only known through their roles, e.g. from ldap, are kept too
+	for _, r := range roles {
+		u := add(r.Username)
+		u.Roles = append(u.Roles, r.Name)
+	}
+	for _, p := range perms {
+		nsPattern, _, _ := strings.Cut(p.Resource, ":")
+		matched := false
+		for i, ns := range m.Namespaces {
+			if !matchResource(nsPattern, ids[i]) {
+				continue
+			}
+			matched = true
+			for _, u := range m.Users {
+				if slices.Contains(u.Roles, p.Role) {
+					u.Access[ns] = mergeAction(u.Access[ns], p.Action)
+				}
+			}
+		}
+		if !matched {
+			m.DanglingPermissions = append(m.DanglingPermissions, p)
+		}
+	}
+	for _, u := range m.Users {
+		if slices.Contains(u.Roles, globalAdminRole) {
+			m.SetGlobalAdmin(u.Name)
+		}
+	}
+	return m
+}
+
+// SetGlobalAdmin flags user as a global admin, who may do anything anywhere.
+func (m *AccessMatrix) SetGlobalAdmin(user string) {
+	for _, u := range m.Users {
+		if u.Name == user {
+			u.GlobalAdmin = true
+			for _, ns := range m.Namespaces {
+				u.Access[ns] = "rw"
+			}
+		}
+	}
+}
+
+// namespaceLabel returns the name a namespace is shown with, the public
+// namespace has an empty id on v1.
+func namespaceLabel(id string) string {
+	if id == "" {
+		return "public"
+	}
+	return id
+}
+
+func mergeAction(a, b string) string {
+	r := strings.Contains(a, "r") || strings.Contains(b, "r")
+	w := strings.Contains(a, "w") || strings.Contains(b, "w")
+	switch {
+	case r && w:
+		return "rw"
+	case r:
+		return "r"
+	case w:
+		return "w"
+	}
+	return ""
+}
+
+func (m *AccessMatrix) header() []string {
+	return append(append([]string{"USER", "ROLES"}, m.Namespaces...), "FLAGS")
+}
+
+func (m *AccessMatrix) rows() [][]string {
+	var rows [][]string
+	for _, u := range m.Users {
+		row := []string{u.Name, strings.Join(u.Roles, ",")}
+		for _, ns := range m.Namespaces {
+			row = append(row, u.Access[ns])
+		}
+		rows = append(rows, append(row, strings.Join(u.Flags(), ",")))
+	}
+	return rows
+}
+
+func (m *AccessMatrix) ToTable(w io.Writer) {
+	tb := newTable(w)
+	var header table.Row
+	for _, h := range m.header() {
+		header = append(header, h)
+	}
+	tb.AppendHeader(header)
+	for _, row := range m.rows() {
+		var r table.Row
+		for _, c := range row {
+			r = append(r, c)
+		}
+		tb.AppendRow(r)
+	}
+	tb.Render()
+	for _, p := range m.DanglingPermissions {
+		fmt.Fprintf(w, "\npermission/%s/%s/%s points at a namespace that does not exist", p.Role, p.Resource, p.Action)
+	}
+}
+
+func (m *AccessMatrix) ToCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(m.header())
+	cw.WriteAll(m.rows())
+	return cw.Error()
+}
+
+func (m *AccessMatrix) WriteToDir(base string) error {
+	if err := os.MkdirAll(base, 0750); err != nil {
+		return err
+	}
+	return writeYamlFile(m, filepath.Join(base, "access-matrix.yaml"))
+}
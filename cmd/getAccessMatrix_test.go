@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessMatrix(t *testing.T) {
+	users := []*nacos.User{{Name: "nacos"}, {Name: "user1"}, {Name: "user2"}}
+	roles := []*nacos.Role{
+		{Name: "ROLE_ADMIN", Username: "nacos"},
+		{Name: "dev", Username: "user1"},
+		{Name: "ops", Username: "user1"},
+		{Name: "dev", Username: "ldap-user"},
+	}
+	perms := []*nacos.Permission{
+		{Role: "dev", Resource: "dev:*:*", Action: "r"},
+		{Role: "ops", Resource: "dev:*:*", Action: "w"},
+		{Role: "ops", Resource: "*:*:*", Action: "r"},
+		{Role: "ops", Resource: "gone:*:*", Action: "rw"},
+	}
+	nss := []*nacos.Namespace{{ID: ""}, {ID: "dev"}}
+	m := NewAccessMatrix(users, roles, perms, nss)
+
+	assert.Equal(t, []string{"public", "dev"}, m.Namespaces)
+	if assert.Len(t, m.Users, 4) {
+		assert.Equal(t, map[string]string{"public": "rw", "dev": "rw"}, m.Users[0].Access)
+		assert.Equal(t, []string{"global-admin"}, m.Users[0].Flags())
+		assert.Equal(t, map[string]string{"public": "r", "dev": "rw"}, m.Users[1].Access)
+		assert.Equal(t, []string{"no-roles"}, m.Users[2].Flags())
+		assert.Equal(t, "ldap-user", m.Users[3].Name)
+		assert.Equal(t, map[string]string{"dev": "r"}, m.Users[3].Access)
+	}
+	assert.Equal(t, []*nacos.Permission{perms[3]}, m.DanglingPermissions)
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, m.ToCSV(&buf))
+		assert.Contains(t, buf.String(), "USER,ROLES,public,dev,FLAGS\n")
+		assert.Contains(t, buf.String(), "user1,\"dev,ops\",r,rw,\n")
+	})
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		m.ToTable(&buf)
+		assert.Contains(t, buf.String(), "global-admin")
+		assert.Contains(t, buf.String(), "permission/ops/gone:*:*/rw points at a namespace that does not exist")
+	})
+
+	t.Run("set global admin", func(t *testing.T) {
+		m.SetGlobalAdmin("user2")
+		assert.Equal(t, []string{"global-admin", "no-roles"}, m.Users[2].Flags())
+	})
+}
+
+func TestAccessMatrixPublicNamespace(t *testing.T) {
+	roles := []*nacos.Role{{Name: "dev", Username: "user1"}}
+	tests := []struct {
+		name     string
+		publicID string
+		resource string
+	}{
+		{"v1", "", ":*:*"},
+		{"v3", "public", "public:*:*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perms := []*nacos.Permission{
+				{Role: "dev", Resource: tt.resource, Action: "r"},
+				// a namespace with the id "public" does not exist on v1
+				{Role: "dev", Resource: "public:*:*", Action: "w"},
+			}
+			m := NewAccessMatrix(nil, roles, perms, []*nacos.Namespace{{ID: tt.publicID}})
+			assert.Equal(t, []string{"public"}, m.Namespaces)
+			if tt.publicID == "" {
+				assert.Equal(t, map[string]string{"public": "r"}, m.Users[0].Access)
+				assert.Equal(t, []*nacos.Permission{perms[1]}, m.DanglingPermissions)
+			} else {
+				assert.Equal(t, map[string]string{"public": "rw"}, m.Users[0].Access)
+				assert.Empty(t, m.DanglingPermissions)
+			}
+		})
+	}
+}
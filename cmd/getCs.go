@@ -16,6 +16,8 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"errors"
 	"os"
 
 	"github.com/joelee2012/nacosctl/pkg/nacos"
@@ -45,6 +47,7 @@ func init() {
 	getCsCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
 	getCsCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
 	getCsCmd.Flags().BoolVarP(&cmdOpts.ShowAll, "all", "A", false, "show all configurations")
+	getCsCmd.Flags().BoolVarP(&cmdOpts.Watch, "watch", "w", false, "after listing the configurations, watch for changes")
 
 }
 
@@ -52,6 +55,7 @@ func GetCs(args []string) {
 	client := NewNacosClient()
 	allCs := new(nacos.ConfigurationList)
 	var err error
+	missing := false
 	if cmdOpts.ShowAll {
 		allCs, err = client.ListAllConfig()
 		cobra.CheckErr(err)
@@ -59,8 +63,8 @@ func GetCs(args []string) {
 		if len(args) > 0 {
 			for _, c := range args {
 				cs, err := client.GetConfig(&nacos.GetCfgOpts{NamespaceID: cmdOpts.NamespaceID, Group: cmdOpts.Group, DataID: c})
-				cobra.CheckErr(err)
-				if cs == nil {
+				if checkNotFound(err, "configuration/"+c) || cs == nil {
+					missing = true
 					continue
 				}
 				allCs.Items = append(allCs.Items, cs)
@@ -71,6 +75,34 @@ func GetCs(args []string) {
 		}
 	}
 	list := NewList(client.APIVersion, allCs.Items, NewConfiguration)
+	if cmdOpts.Watch {
+		WatchCs(client, allCs.Items)
+		return
+	}
 	// toJson(list, os.Stdout)
 	cobra.CheckErr(WriteFormat(list, cmdOpts.Output, os.Stdout))
+	if missing {
+		os.Exit(1)
+	}
+}
+
+// WatchCs prints the configurations, then one more entry every time one of
+// them changes on the server.
+func WatchCs(client *nacos.Client, items []*nacos.Configuration) {
+	if len(items) == 0 {
+		cobra.CheckErr("no configurations to watch")
+	}
+	watcher := client.NewWatcher()
+	for i, cfg := range items {
+		watcher.Add(&nacos.GetCfgOpts{DataID: cfg.DataID, Group: cfg.GetGroup(), NamespaceID: cfg.GetNamespace()}, nacos.ContentMd5(cfg))
+		cobra.CheckErr(WriteEvent(NewConfiguration(client.APIVersion, cfg), cmdOpts.Output, i == 0, os.Stdout))
+	}
+	err := watcher.Watch(func(cfg *nacos.Configuration) error {
+		return WriteEvent(NewConfiguration(client.APIVersion, cfg), cmdOpts.Output, false, os.Stdout)
+	})
+	// interrupting is the way to stop watching
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	cobra.CheckErr(err)
 }
@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// getHistoryCsCmd represents the get history cs command
+var getHistoryCsCmd = &cobra.Command{
+	Use:     "cs [flags] name [historyId]",
+	Aliases: []string{"configuration"},
+	Short:   "Display the history of one configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		GetCsHistory(args)
+	},
+	Args: cobra.RangeArgs(1, 2),
+}
+
+func init() {
+	getHistoryCmd.AddCommand(getHistoryCsCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// getHistoryCsCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	getHistoryCsCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	getHistoryCsCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+}
+
+func GetCsHistory(args []string) {
+	client := NewNacosClient()
+	hs := new(nacos.ConfigHistoryList)
+	if len(args) > 1 {
+		h, err := client.GetConfigHistory(&nacos.GetHistoryOpts{ID: args[1], DataID: args[0], Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID})
+		cobra.CheckErr(err)
+		hs.Items = append(hs.Items, h)
+	} else {
+		var err error
+		hs, err = client.ListConfigHistory(&nacos.GetCfgOpts{DataID: args[0], Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID})
+		cobra.CheckErr(err)
+	}
+	list := NewList(client.APIVersion, hs.Items, NewConfigHistory)
+	cobra.CheckErr(WriteFormat(list, cmdOpts.Output, os.Stdout))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+type InstanceOpts struct {
+	Service   string
+	Clusters  []string
+	Unhealthy bool
+	Disabled  bool
+}
+
+var instOpts InstanceOpts
+
+// getInstanceCmd represents the getInstance command
+var getInstanceCmd = &cobra.Command{
+	Use:     "instance",
+	Aliases: []string{"inst"},
+	Short:   "Display the instances of one service",
+	Run: func(cmd *cobra.Command, args []string) {
+		GetInstance()
+	},
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	getCmd.AddCommand(getInstanceCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// getInstanceCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	getInstanceCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	getInstanceCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	getInstanceCmd.Flags().StringVarP(&instOpts.Service, "service", "S", "", "name of service")
+	getInstanceCmd.MarkFlagRequired("service")
+	getInstanceCmd.Flags().StringSliceVarP(&instOpts.Clusters, "cluster", "c", nil, "only show instances of these clusters")
+	getInstanceCmd.Flags().BoolVar(&instOpts.Unhealthy, "unhealthy", false, "only show unhealthy instances")
+	getInstanceCmd.Flags().BoolVar(&instOpts.Disabled, "disabled", false, "only show disabled instances")
+}
+
+func GetInstance() {
+	client := NewNacosClient()
+	insts, err := client.ListInstance(&nacos.ListInstOpts{
+		NamespaceID: cmdOpts.NamespaceID,
+		GroupName:   cmdOpts.Group,
+		ServiceName: instOpts.Service,
+		Clusters:    instOpts.Clusters,
+	})
+	cobra.CheckErr(err)
+	list := NewList(client.APIVersion, insts.Items, NewInstance)
+	FilterInstances(list, &instOpts)
+	cobra.CheckErr(WriteFormat(list, cmdOpts.Output, os.Stdout))
+}
+
+// FilterInstances drops the instances that don't match the --unhealthy and
+// --disabled flags.
+func FilterInstances(list *InstanceList, opts *InstanceOpts) {
+	list.Filter(func(i Instance) bool {
+		return (!opts.Unhealthy || !i.Status.Healthy) && (!opts.Disabled || !i.Spec.Enabled)
+	})
+}
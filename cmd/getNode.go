@@ -0,0 +1,61 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var nodeState string
+
+// getNodeCmd represents the getNode command
+var getNodeCmd = &cobra.Command{
+	Use:     "node [address]",
+	Aliases: []string{"nodes"},
+	Short:   "Display the members of the nacos cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		GetNode(args)
+	},
+}
+
+func init() {
+	getCmd.AddCommand(getNodeCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// getNodeCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	getNodeCmd.Flags().StringVar(&nodeState, "state", "", "only show the nodes in this state, e.g. UP, DOWN or SUSPICIOUS")
+}
+
+func GetNode(args []string) {
+	client := NewNacosClient()
+	nodes, err := client.ListClusterNodes()
+	cobra.CheckErr(err)
+	list := NewList(client.APIVersion, nodes.Items, NewNode)
+	list.Filter(func(n Node) bool {
+		return (len(args) == 0 || slices.Contains(args, n.Metadata.Name)) &&
+			(nodeState == "" || strings.EqualFold(n.Status.State, nodeState))
+	})
+	cobra.CheckErr(WriteFormat(list, cmdOpts.Output, os.Stdout))
+}
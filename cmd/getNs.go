@@ -51,6 +51,7 @@ func GetNamespace(args []string) {
 	client := NewNacosClient()
 	nss, err := client.ListNamespace()
 	cobra.CheckErr(err)
+	missing := false
 	if len(args) > 0 {
 		var items []*nacos.Namespace
 		for _, ns := range nss.Items {
@@ -59,7 +60,13 @@ func GetNamespace(args []string) {
 			}
 		}
 		nss.Items = items
+		missing = printNotFound("namespace", args, func(id string) bool {
+			return slices.ContainsFunc(items, func(ns *nacos.Namespace) bool { return ns.ID == id })
+		})
 	}
 	list := NewList(client.APIVersion, nss.Items, NewNamespace)
 	cobra.CheckErr(WriteFormat(list, cmdOpts.Output, os.Stdout))
+	if missing {
+		os.Exit(1)
+	}
 }
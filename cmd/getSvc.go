@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// getSvcCmd represents the getSvc command
+var getSvcCmd = &cobra.Command{
+	Use:     "svc [name]",
+	Aliases: []string{"service"},
+	Short:   "Display one or many services",
+	Run: func(cmd *cobra.Command, args []string) {
+		GetSvc(args)
+	},
+}
+
+func init() {
+	getCmd.AddCommand(getSvcCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// getSvcCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	getSvcCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	getSvcCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+}
+
+func GetSvc(args []string) {
+	client := NewNacosClient()
+	svcs := new(nacos.ServiceList)
+	missing := false
+	if len(args) > 0 {
+		for _, name := range args {
+			svc, err := FindService(client, &nacos.GetSvcOpts{NamespaceID: cmdOpts.NamespaceID, GroupName: cmdOpts.Group, ServiceName: name})
+			cobra.CheckErr(err)
+			// the v1 api does not answer a missing service with 404
+			if svc == nil {
+				fmt.Fprintf(os.Stderr, "service/%s not found\n", name)
+				missing = true
+				continue
+			}
+			svcs.Items = append(svcs.Items, svc)
+		}
+	} else {
+		var err error
+		svcs, err = client.ListService(&nacos.ListSvcOpts{NamespaceID: cmdOpts.NamespaceID, GroupName: cmdOpts.Group})
+		cobra.CheckErr(err)
+		if cmdOpts.Output != "table" {
+			cobra.CheckErr(fillServiceSpec(client, svcs.Items))
+		}
+	}
+	list := NewList(client.APIVersion, svcs.Items, NewService)
+	cobra.CheckErr(WriteFormat(list, cmdOpts.Output, os.Stdout))
+	if missing {
+		os.Exit(1)
+	}
+}
+
+// fillServiceSpec gets the protect threshold, metadata and selector of the
+// listed services, which the list api leaves out, so the manifests written
+// by -o can be applied again.
+func fillServiceSpec(client *nacos.Client, items []*nacos.Service) error {
+	for _, svc := range items {
+		detail, err := client.GetService(&nacos.GetSvcOpts{NamespaceID: svc.NamespaceID, GroupName: svc.GroupName, ServiceName: svc.GetName()})
+		if err != nil {
+			return err
+		}
+		svc.ProtectThreshold = detail.ProtectThreshold
+		svc.Metadata = detail.Metadata
+		svc.Selector = detail.Selector
+	}
+	return nil
+}
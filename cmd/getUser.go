@@ -50,6 +50,7 @@ func getUser(args []string) {
 	client := NewNacosClient()
 	users, err := client.ListUser()
 	cobra.CheckErr(err)
+	missing := false
 	if len(args) > 0 {
 		var us []*nacos.User
 		for _, u := range users.Items {
@@ -58,7 +59,13 @@ func getUser(args []string) {
 			}
 		}
 		users.Items = us
+		missing = printNotFound("user", args, func(name string) bool {
+			return slices.ContainsFunc(us, func(u *nacos.User) bool { return u.Name == name })
+		})
 	}
 	list := NewList(client.APIVersion, users.Items, NewUser)
 	cobra.CheckErr(WriteFormat(list, cmdOpts.Output, os.Stdout))
+	if missing {
+		os.Exit(1)
+	}
 }
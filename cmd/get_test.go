@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNotFound(t *testing.T) {
+	err := fmt.Errorf("get: %w", &nacos.NacosErr{StatusCode: http.StatusNotFound, URL: "user missing"})
+	assert.True(t, checkNotFound(err, "user/missing"))
+	assert.False(t, checkNotFound(nil, "user/test"))
+}
+
+func TestPrintNotFound(t *testing.T) {
+	found := func(name string) bool { return slices.Contains([]string{"a", "b"}, name) }
+	assert.False(t, printNotFound("user", []string{"a", "b"}, found))
+	assert.True(t, printNotFound("user", []string{"a", "c"}, found))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [flags]",
+	Short: "Import configurations from a nacos console zip archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		ImportCs()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// importCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	importCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id to import into")
+	importCmd.Flags().StringVarP(&archiveOpts.File, "file", "f", "", "the zip file to read")
+	importCmd.MarkFlagRequired("file")
+	importCmd.Flags().StringVar(&archiveOpts.Policy, "policy", "abort", "what to do with existing configurations[abort, skip, overwrite]")
+}
+
+func ImportCs() {
+	policy, err := nacos.ParseConflictPolicy(archiveOpts.Policy)
+	cobra.CheckErr(err)
+	f, err := os.Open(archiveOpts.File)
+	cobra.CheckErr(err)
+	defer f.Close()
+	fi, err := f.Stat()
+	cobra.CheckErr(err)
+	cs, err := ReadArchive(f, fi.Size(), cmdOpts.NamespaceID)
+	cobra.CheckErr(err)
+	client := NewNacosClient()
+	report, err := PublishConfigs(client, cs, policy)
+	report.ToTable(os.Stdout)
+	cobra.CheckErr(err)
+}
+
+// PublishConfigs creates the configurations, the ones that already exist
+// are handled according to policy. With PolicyAbort nothing is published
+// when any of them exists.
+func PublishConfigs(client *nacos.Client, cs []*Configuration, policy nacos.ConflictPolicy) (Report, error) {
+	var report Report
+	existing := map[string]bool{}
+	listed := map[string]bool{}
+	for _, c := range cs {
+		if listed[c.Metadata.Namespace] {
+			continue
+		}
+		items, err := client.ListConfigInNs(c.Metadata.Namespace, "")
+		if err != nil {
+			return report, err
+		}
+		// keyed by the namespace as queried, v3 answers "public" for the empty one
+		for _, it := range items.Items {
+			existing[configKey(c.Metadata.Namespace, it.GetGroup(), it.DataID)] = true
+		}
+		listed[c.Metadata.Namespace] = true
+	}
+	if policy == nacos.PolicyAbort {
+		for _, c := range cs {
+			if existing[configKey(c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)] {
+				return report, fmt.Errorf("configuration/%s/%s/%s already exists, nothing imported", c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)
+			}
+		}
+	}
+	for _, c := range cs {
+		name := fmt.Sprintf("configuration/%s/%s/%s", c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)
+		action := "created"
+		if existing[configKey(c.Metadata.Namespace, c.Metadata.Group, c.Metadata.DataID)] {
+			if policy == nacos.PolicySkip {
+				report.Add(name, "skipped")
+				continue
+			}
+			action = "overwritten"
+		}
+		err := client.CreateConfig(&nacos.CreateCfgOpts{
+			DataID:      c.Metadata.DataID,
+			Group:       c.Metadata.Group,
+			NamespaceID: c.Metadata.Namespace,
+			Content:     c.Spec.Content,
+			Type:        c.Spec.Type,
+			Description: c.Spec.Description,
+			Application: c.Spec.Application,
+			Tags:        c.Spec.Tags,
+		})
+		if err != nil {
+			return report, err
+		}
+		report.Add(name, action)
+	}
+	return report, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// loginCmd represents the login command
+var loginCmd = &cobra.Command{
+	Use:   "login [context]",
+	Short: "Log in to a server and cache the access token",
+	Long: `Log in to the server of a context, the current one by default, and cache
+the access token so the next commands reuse it until it is about to expire.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := cliConfig.Context
+		if len(args) > 0 {
+			name = args[0]
+		}
+		client := NewContextClient(name)
+		err := client.Login()
+		if nacos.IsUnauthorized(err) {
+			err = fmt.Errorf("%w\ncheck the user and password of context %s", err, name)
+		}
+		cobra.CheckErr(err)
+		fmt.Printf("logged in to %s as %s, token expires at %s\n", client.URL, client.User, time.Unix(client.ExpiredAt, 0).Format(time.RFC3339))
+	},
+	Args: cobra.MaximumNArgs(1),
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// loginCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// loginCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
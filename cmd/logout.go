@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+var logoutAll bool
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout [context]",
+	Short: "Remove the cached access token of a server",
+	Run: func(cmd *cobra.Command, args []string) {
+		if logoutAll {
+			dir, err := tokenDir()
+			cobra.CheckErr(err)
+			cobra.CheckErr(os.RemoveAll(dir))
+			fmt.Println("logged out of all servers")
+			return
+		}
+		name := cliConfig.Context
+		if len(args) > 0 {
+			name = args[0]
+		}
+		// the store is built without a client, which would reach the server
+		// to detect its version, so logging out works offline
+		server := cliConfig.GetServer(name)
+		if server == nil {
+			cobra.CheckErr(fmt.Errorf("server %s not found in config file: %s", name, cmdOpts.ConfigFile))
+		}
+		path, err := tokenPath(name, server)
+		cobra.CheckErr(err)
+		store := &nacos.FileTokenStore{Path: path}
+		cobra.CheckErr(store.Delete())
+		fmt.Printf("logged out of %s\n", server.Addresses()[0])
+	},
+	Args: cobra.MaximumNArgs(1),
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// logoutCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "remove the cached tokens of all servers")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"strconv"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// InstancePatch holds the changes of patch instance, nil fields are kept.
+type InstancePatch struct {
+	Enabled        *bool
+	Weight         *float64
+	Metadata       map[string]string
+	RemoveMetadata []string
+}
+
+// Apply sets the changes of p on inst.
+func (p *InstancePatch) Apply(inst *nacos.Instance) {
+	if p.Enabled != nil {
+		inst.Enabled = *p.Enabled
+	}
+	if p.Weight != nil {
+		inst.Weight = *p.Weight
+	}
+	if len(p.Metadata) > 0 && inst.Metadata == nil {
+		inst.Metadata = map[string]string{}
+	}
+	maps.Copy(inst.Metadata, p.Metadata)
+	for _, k := range p.RemoveMetadata {
+		delete(inst.Metadata, k)
+	}
+}
+
+var patchInstOpts struct {
+	Cluster string
+	Enabled bool
+	Weight  float64
+	InstancePatch
+}
+
+// patchInstanceCmd represents the patch instance command
+var patchInstanceCmd = &cobra.Command{
+	Use:     "instance [flags] ip:port",
+	Aliases: []string{"inst"},
+	Short:   "Enable, disable, reweight or relabel one instance",
+	Run: func(cmd *cobra.Command, args []string) {
+		patch := &patchInstOpts.InstancePatch
+		if cmd.Flags().Changed("enabled") {
+			patch.Enabled = &patchInstOpts.Enabled
+		}
+		if cmd.Flags().Changed("weight") {
+			patch.Weight = &patchInstOpts.Weight
+		}
+		PatchInstance(args[0], patch)
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	patchCmd.AddCommand(patchInstanceCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// patchInstanceCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	patchInstanceCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	patchInstanceCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	patchInstanceCmd.Flags().StringVarP(&instOpts.Service, "service", "S", "", "name of service")
+	patchInstanceCmd.MarkFlagRequired("service")
+	patchInstanceCmd.Flags().StringVarP(&patchInstOpts.Cluster, "cluster", "c", "", "cluster of the instance, required if the address is in several clusters")
+	patchInstanceCmd.Flags().BoolVar(&patchInstOpts.Enabled, "enabled", true, "enable or disable (--enabled=false) the instance")
+	patchInstanceCmd.Flags().Float64Var(&patchInstOpts.Weight, "weight", 1, "weight of the instance")
+	patchInstanceCmd.Flags().StringToStringVarP(&patchInstOpts.Metadata, "metadata", "m", nil, "metadata to add or change, e.g. key1=value1,key2=value2")
+	patchInstanceCmd.Flags().StringSliceVar(&patchInstOpts.RemoveMetadata, "remove-metadata", nil, "metadata keys to remove")
+}
+
+func PatchInstance(addr string, patch *InstancePatch) {
+	client := NewNacosClient()
+	insts, err := client.ListInstance(&nacos.ListInstOpts{NamespaceID: cmdOpts.NamespaceID, GroupName: cmdOpts.Group, ServiceName: instOpts.Service})
+	cobra.CheckErr(err)
+	inst, err := findInstance(insts.Items, addr, patchInstOpts.Cluster)
+	cobra.CheckErr(err)
+	patch.Apply(inst)
+	cobra.CheckErr(client.UpdateInstance(inst))
+	fmt.Printf("instance/%s patched\n", addr)
+}
+
+// findInstance returns the instance listening on addr, in cluster when it
+// is not empty.
+func findInstance(items []*nacos.Instance, addr, cluster string) (*nacos.Instance, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	var found []*nacos.Instance
+	for _, inst := range items {
+		if inst.IP == host && strconv.Itoa(inst.Port) == port && (cluster == "" || inst.ClusterName == cluster) {
+			found = append(found, inst)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("instance %s not found", addr)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("instance %s is in several clusters, use --cluster to choose one", addr)
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindInstance(t *testing.T) {
+	items := []*nacos.Instance{
+		{IP: "10.0.0.1", Port: 8080, ClusterName: "c1"},
+		{IP: "10.0.0.1", Port: 8080, ClusterName: "c2"},
+		{IP: "10.0.0.2", Port: 8080, ClusterName: "c1"},
+	}
+	tests := []struct {
+		addr    string
+		cluster string
+		want    *nacos.Instance
+		err     string
+	}{
+		{"10.0.0.2:8080", "", items[2], ""},
+		{"10.0.0.1:8080", "c2", items[1], ""},
+		{"10.0.0.1:8080", "", nil, "several clusters"},
+		{"10.0.0.3:8080", "", nil, "not found"},
+		{"10.0.0.3", "", nil, "missing port"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr+tt.cluster, func(t *testing.T) {
+			got, err := findInstance(items, tt.addr, tt.cluster)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Same(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInstancePatchApply(t *testing.T) {
+	enabled, weight := false, 0.5
+	inst := &nacos.Instance{Enabled: true, Weight: 1, Metadata: map[string]string{"a": "1", "b": "2"}}
+	patch := &InstancePatch{Enabled: &enabled, Weight: &weight, Metadata: map[string]string{"c": "3"}, RemoveMetadata: []string{"a"}}
+	patch.Apply(inst)
+	assert.False(t, inst.Enabled)
+	assert.Equal(t, 0.5, inst.Weight)
+	assert.Equal(t, map[string]string{"b": "2", "c": "3"}, inst.Metadata)
+
+	inst = &nacos.Instance{Enabled: true, Weight: 1}
+	(&InstancePatch{Metadata: map[string]string{"c": "3"}}).Apply(inst)
+	assert.True(t, inst.Enabled)
+	assert.Equal(t, 1.0, inst.Weight)
+	assert.Equal(t, map[string]string{"c": "3"}, inst.Metadata)
+}
+
+// startInstanceServer starts a v1 server with an instance in the DEFAULT
+// cluster of svc1, makes it the context of the commands and returns the
+// queries of the requests changing the instance.
+func startInstanceServer(t *testing.T) *[]url.Values {
+	var changes []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/console/server/state":
+			w.Write([]byte(`{"version": "2.4.0"}`))
+		case "/v1/auth/login":
+			w.Write([]byte(`{"accessToken": "test-token", "tokenTtl": 3600}`))
+		case "/v1/ns/service":
+			w.Write([]byte(`{"name": "DEFAULT_GROUP@@svc1", "groupName": "DEFAULT_GROUP", "clusters": [{"name": "DEFAULT"}]}`))
+		case "/v1/ns/catalog/instances":
+			// the catalog api matches the exact cluster name
+			if r.URL.Query().Get("clusterName") != "DEFAULT" {
+				w.Write([]byte(`{"count": 0, "list": []}`))
+				return
+			}
+			w.Write([]byte(`{"count": 1, "list": [{"ip": "10.0.0.1", "port": 8080, "weight": 1.0, "healthy": true, "enabled": true, "ephemeral": true, "clusterName": "DEFAULT"}]}`))
+		case "/v1/ns/instance":
+			q := r.URL.Query()
+			q.Set("method", r.Method)
+			changes = append(changes, q)
+			w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	saved, savedOpts, savedService := cliConfig, cmdOpts, instOpts.Service
+	t.Cleanup(func() { cliConfig, cmdOpts, instOpts.Service = saved, savedOpts, savedService })
+	cliConfig = CLIConfig{Context: "test", Servers: map[string]*Server{"test": {URL: ts.URL, User: "user", Password: "password"}}}
+	cmdOpts.Group = "DEFAULT_GROUP"
+	instOpts.Service = "svc1"
+	return &changes
+}
+
+func TestPatchInstanceDefaultCluster(t *testing.T) {
+	changes := startInstanceServer(t)
+	enabled := false
+	PatchInstance("10.0.0.1:8080", &InstancePatch{Enabled: &enabled})
+	if assert.Len(t, *changes, 1) {
+		q := (*changes)[0]
+		assert.Equal(t, http.MethodPut, q.Get("method"))
+		assert.Equal(t, "DEFAULT", q.Get("clusterName"))
+		assert.Equal(t, "false", q.Get("enabled"))
+	}
+}
+
+func TestDeregisterDefaultCluster(t *testing.T) {
+	changes := startInstanceServer(t)
+	Deregister("10.0.0.1:8080")
+	if assert.Len(t, *changes, 1) {
+		q := (*changes)[0]
+		assert.Equal(t, http.MethodDelete, q.Get("method"))
+		assert.Equal(t, "DEFAULT", q.Get("clusterName"))
+		assert.Equal(t, "true", q.Get("ephemeral"))
+	}
+}
+
+func TestDrainSvcDefaultCluster(t *testing.T) {
+	changes := startInstanceServer(t)
+	// the drain ends at once and the instance is enabled again
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	DrainSvc(ctx, "svc1")
+	var got []string
+	for _, q := range *changes {
+		got = append(got, fmt.Sprintf("%s %s enabled=%s", q.Get("method"), q.Get("clusterName"), q.Get("enabled")))
+	}
+	assert.Equal(t, []string{"PUT DEFAULT enabled=false", "PUT DEFAULT enabled=true"}, got)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// patchUserCmd represents the patch user command
+var patchUserCmd = &cobra.Command{
+	Use:     "user name",
+	Aliases: []string{"u"},
+	Short:   "Change the password of one user",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := NewNacosClient()
+		password, err := userPassword.Resolve()
+		cobra.CheckErr(err)
+		cobra.CheckErr(client.UpdateUser(args[0], password))
+		fmt.Printf("user/%s patched\n", args[0])
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	patchCmd.AddCommand(patchUserCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// patchUserCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	addPasswordFlags(patchUserCmd)
+}
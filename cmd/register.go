@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+type RegisterOpts struct {
+	IP        string
+	Port      int
+	Cluster   string
+	Weight    float64
+	Metadata  map[string]string
+	KeepAlive bool
+}
+
+var registerOpts RegisterOpts
+
+// registerCmd represents the register command
+var registerCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register an instance of a service",
+	Long: `Register an instance of a service.
+
+With --keepalive the instance is ephemeral: the command keeps it registered by
+sending heartbeats and deregisters it when interrupted (Ctrl-C).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		Register(cmd.Context())
+	},
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(registerCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// registerCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	registerCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	registerCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	registerCmd.Flags().StringVar(&instOpts.Service, "service", "", "name of service")
+	registerCmd.MarkFlagRequired("service")
+	registerCmd.Flags().StringVar(&registerOpts.IP, "ip", "", "ip of the instance, the first non loopback address of this host by default")
+	registerCmd.Flags().IntVarP(&registerOpts.Port, "port", "p", 0, "port of the instance")
+	registerCmd.MarkFlagRequired("port")
+	registerCmd.Flags().StringVarP(&registerOpts.Cluster, "cluster", "c", "DEFAULT", "cluster of the instance")
+	registerCmd.Flags().Float64Var(&registerOpts.Weight, "weight", 1, "weight of the instance")
+	registerCmd.Flags().StringToStringVarP(&registerOpts.Metadata, "metadata", "m", nil, "metadata of the instance, e.g. key1=value1,key2=value2")
+	registerCmd.Flags().BoolVar(&registerOpts.KeepAlive, "keepalive", false, "register an ephemeral instance and keep it alive until interrupted")
+}
+
+func Register(ctx context.Context) {
+	client := NewNacosClient()
+	if registerOpts.IP == "" {
+		ip, err := localIP()
+		cobra.CheckErr(err)
+		registerOpts.IP = ip
+	}
+	inst := &nacos.Instance{
+		NamespaceID: cmdOpts.NamespaceID,
+		GroupName:   cmdOpts.Group,
+		ServiceName: instOpts.Service,
+		ClusterName: registerOpts.Cluster,
+		IP:          registerOpts.IP,
+		Port:        registerOpts.Port,
+		Weight:      registerOpts.Weight,
+		Metadata:    registerOpts.Metadata,
+		Enabled:     true,
+		Healthy:     true,
+	}
+	addr := net.JoinHostPort(inst.IP, fmt.Sprint(inst.Port))
+	if !registerOpts.KeepAlive {
+		cobra.CheckErr(client.RegisterInstance(inst))
+		fmt.Printf("instance/%s registered\n", addr)
+		return
+	}
+	ka := client.NewKeepAlive(inst)
+	ka.OnBeat = func(_ *nacos.BeatResult, err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "instance/%s beat failed: %v\n", addr, err)
+		}
+	}
+	fmt.Printf("instance/%s registered, press Ctrl-C to deregister\n", addr)
+	cobra.CheckErr(ka.RunCtx(ctx))
+	fmt.Printf("instance/%s deregistered\n", addr)
+}
+
+// localIP returns the first non loopback ipv4 address of this host.
+func localIP() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			return ipnet.IP.String(), nil
+		}
+	}
+	return "", errors.New("no ip address found, use --ip to set one")
+}
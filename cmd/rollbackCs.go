@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// rollbackCsCmd represents the rollback cs command
+var rollbackCsCmd = &cobra.Command{
+	Use:     "cs [flags] name",
+	Aliases: []string{"configuration"},
+	Short:   "Republish the content of one configuration history entry",
+	Run: func(cmd *cobra.Command, args []string) {
+		RollbackCs(args[0])
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var rollbackOpts struct {
+	To string
+}
+
+func init() {
+	rollbackCmd.AddCommand(rollbackCsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// rollbackCsCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	rollbackCsCmd.Flags().StringVarP(&cmdOpts.NamespaceID, "namespace", "n", "", "namespace id")
+	rollbackCsCmd.Flags().StringVarP(&cmdOpts.Group, "group", "g", "DEFAULT_GROUP", "group name")
+	rollbackCsCmd.Flags().StringVar(&rollbackOpts.To, "to", "", "history id to roll back to")
+	rollbackCsCmd.MarkFlagRequired("to")
+}
+
+func RollbackCs(dataID string) {
+	client := NewNacosClient()
+	h, err := client.GetConfigHistory(&nacos.GetHistoryOpts{ID: rollbackOpts.To, DataID: dataID, Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID})
+	cobra.CheckErr(err)
+	opts := &nacos.CreateCfgOpts{
+		DataID:      dataID,
+		Group:       cmdOpts.Group,
+		NamespaceID: cmdOpts.NamespaceID,
+		Content:     h.Content,
+		Application: h.Application,
+	}
+	// history entries do not record type, description and tags, keep the
+	// ones of the current configuration if it still exists
+	cfg, err := client.GetConfig(&nacos.GetCfgOpts{DataID: dataID, Group: cmdOpts.Group, NamespaceID: cmdOpts.NamespaceID})
+	if err != nil && !nacos.IsNotFound(err) {
+		cobra.CheckErr(err)
+	}
+	if cfg != nil {
+		opts.Type = cfg.Type
+		opts.Description = cfg.Description
+		opts.Tags = cfg.Tags
+		if opts.Application == "" {
+			opts.Application = cfg.Application
+		}
+	}
+	cobra.CheckErr(client.CreateConfig(opts))
+	fmt.Printf("configuration/%s/%s/%s rolled back to history/%s\n", cmdOpts.NamespaceID, cmdOpts.Group, dataID, rollbackOpts.To)
+}
@@ -16,9 +16,16 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"log"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 
 	"github.com/joelee2012/nacosctl/pkg/nacos"
 	"github.com/spf13/cobra"
@@ -31,6 +38,8 @@ type CmdOpts struct {
 	OutDir      string
 	ConfigFile  string
 	ShowAll     bool
+	Watch       bool
+	Verbose     bool
 }
 
 var cmdOpts = CmdOpts{}
@@ -51,7 +60,14 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// the first interrupt cancels the requests in flight, a second one kills
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -64,6 +80,7 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVarP(&cmdOpts.ConfigFile, "setting", "s", "", "config file (default is $HOME/.nacos.yaml)")
+	rootCmd.PersistentFlags().BoolVarP(&cmdOpts.Verbose, "verbose", "v", false, "print the retries of failed requests")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -86,6 +103,52 @@ func NewNacosClient() *nacos.Client {
 	if cliConfig.Context == "" {
 		cobra.CheckErr(fmt.Errorf("no context set in config file: %s", cmdOpts.ConfigFile))
 	}
-	server := cliConfig.GetCurrentServer()
-	return nacos.NewClient(server.URL, server.User, server.Password)
+	return NewContextClient(cliConfig.Context)
+}
+
+// NewContextClient returns a client for the server of the named context.
+func NewContextClient(name string) *nacos.Client {
+	server := cliConfig.GetServer(name)
+	if server == nil {
+		cobra.CheckErr(fmt.Errorf("server %s not found in config file: %s", name, cmdOpts.ConfigFile))
+	}
+	hc, err := server.HTTPClient()
+	cobra.CheckErr(err)
+	urls := server.Addresses()
+	// the version detection of NewClient is canceled by an interrupt too
+	opts := []nacos.Option{nacos.WithContext(rootCmd.Context()), nacos.WithHTTPClient(hc), nacos.WithURLs(urls...)}
+	if server.Retry != nil {
+		opts = append(opts, nacos.WithRetryPolicy(*server.Retry))
+	}
+	if cmdOpts.Verbose {
+		opts = append(opts, nacos.WithLogger(log.New(os.Stderr, "", log.LstdFlags)))
+	}
+	client := nacos.NewClient(urls[0], server.User, server.Password, opts...)
+	// the password is only resolved when a login needs it, a cached token
+	// saves running a password command
+	client.PasswordFunc = server.ResolvePassword
+	if path, err := tokenPath(name, server); err == nil {
+		client.TokenStore = &nacos.FileTokenStore{Path: path}
+	}
+	return client
+}
+
+// tokenDir is where the tokens of all contexts are cached.
+func tokenDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "nacosctl", "tokens"), nil
+}
+
+// tokenPath returns where the token of the context is cached. The url and
+// the user are part of the name, so editing the context drops the token.
+func tokenPath(name string, server *Server) (string, error) {
+	dir, err := tokenDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.Join(server.Addresses(), ",") + "\x00" + server.User))
+	return filepath.Join(dir, fmt.Sprintf("%s-%x.json", url.PathEscape(name), sum[:6])), nil
 }
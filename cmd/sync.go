@@ -0,0 +1,244 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync [flags]",
+	Short: "Copy namespaces and configurations from one server to another",
+	Long: `Copy namespaces and configurations one way, from the server of one
+context to the server of another context in the config file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if syncOpts.From == syncOpts.To {
+			cobra.CheckErr("--from and --to must be different contexts")
+		}
+		src := NewContextClient(syncOpts.From)
+		dst := NewContextClient(syncOpts.To)
+		report, err := Sync(src, dst, &syncOpts)
+		report.ToTable(os.Stdout)
+		cobra.CheckErr(err)
+	},
+}
+
+type SyncOpts struct {
+	From       string
+	To         string
+	Namespaces []string
+	Groups     []string
+	DryRun     bool
+	Delete     bool
+}
+
+var syncOpts SyncOpts
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// syncCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	syncCmd.Flags().StringVar(&syncOpts.From, "from", "", "context of the source server")
+	syncCmd.MarkFlagRequired("from")
+	syncCmd.Flags().StringVar(&syncOpts.To, "to", "", "context of the destination server")
+	syncCmd.MarkFlagRequired("to")
+	syncCmd.Flags().StringSliceVarP(&syncOpts.Namespaces, "namespace", "n", nil, "only sync these namespace ids, all namespaces if empty")
+	syncCmd.Flags().StringSliceVarP(&syncOpts.Groups, "group", "g", nil, "only sync configurations of these groups, all groups if empty")
+	syncCmd.Flags().BoolVar(&syncOpts.DryRun, "dry-run", false, "only report what would be synced")
+	syncCmd.Flags().BoolVar(&syncOpts.Delete, "delete", false, "delete configurations of the destination that are not in the source")
+}
+
+// Sync copies the namespaces and configurations selected by opts from src
+// to dst, and reports what was done to each of them.
+func Sync(src, dst *nacos.Client, opts *SyncOpts) (Report, error) {
+	var report Report
+	action := func(a string) string {
+		if opts.DryRun {
+			return a + " (dry run)"
+		}
+		return a
+	}
+	srcNss, err := src.ListNamespace()
+	if err != nil {
+		return report, err
+	}
+	dstNss, err := dst.ListNamespace()
+	if err != nil {
+		return report, err
+	}
+	for _, ns := range srcNss.Items {
+		// the public namespace has an empty id on v1 and "public" on v3, it
+		// is matched by its label and always exists
+		label := namespaceLabel(ns.ID)
+		if len(opts.Namespaces) > 0 && !slices.Contains(opts.Namespaces, ns.ID) && !slices.Contains(opts.Namespaces, label) {
+			continue
+		}
+		name := "namespace/" + label
+		idx := slices.IndexFunc(dstNss.Items, func(n *nacos.Namespace) bool { return namespaceLabel(n.ID) == label })
+		dstID := ns.ID
+		switch {
+		case idx >= 0:
+			dstID = dstNss.Items[idx].ID
+		case label == "public":
+			dstID = publicID(dst)
+		}
+		switch {
+		case label == "public" || idx >= 0 && dstNss.Items[idx].Name == ns.Name && dstNss.Items[idx].Description == ns.Description:
+			report.Add(name, "unchanged")
+		default:
+			if !opts.DryRun {
+				if err := dst.CreateOrUpdateNamespace(&nacos.CreateNsOpts{ID: ns.ID, Name: ns.Name, Description: ns.Description}); err != nil {
+					return report, err
+				}
+			}
+			report.Add(name, action(createdOrUpdated(idx >= 0)))
+		}
+		if err := syncConfigs(src, dst, ns.ID, dstID, idx >= 0 || label == "public", opts, &report, action); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// publicID returns the id of the public namespace on the server of client.
+func publicID(client *nacos.Client) string {
+	if client.APIVersion == "v1" {
+		return ""
+	}
+	return "public"
+}
+
+// syncConfigs copies the configurations of the namespace srcNs of src to the
+// namespace dstNs of dst, the ids differ for the public namespace.
+func syncConfigs(src, dst *nacos.Client, srcNs, dstNs string, nsExists bool, opts *SyncOpts, report *Report, action func(string) string) error {
+	inGroups := func(c *nacos.Configuration) bool {
+		return len(opts.Groups) == 0 || slices.Contains(opts.Groups, c.GetGroup())
+	}
+	namespace := namespaceLabel(srcNs)
+	srcCs, err := src.ListConfigInNs(srcNs, "")
+	if err != nil {
+		return err
+	}
+	dstCs := map[string]*nacos.Configuration{}
+	if nsExists {
+		cs, err := dst.ListConfigInNs(dstNs, "")
+		if err != nil {
+			return err
+		}
+		for _, c := range cs.Items {
+			if inGroups(c) {
+				dstCs[c.GetGroup()+"/"+c.DataID] = c
+			}
+		}
+	}
+	for _, c := range srcCs.Items {
+		if !inGroups(c) {
+			continue
+		}
+		key := c.GetGroup() + "/" + c.DataID
+		name := fmt.Sprintf("configuration/%s/%s", namespace, key)
+		d, exists := dstCs[key]
+		delete(dstCs, key)
+		if exists {
+			same, err := sameConfig(src, dst, c, d)
+			if err != nil {
+				return err
+			}
+			if same {
+				report.Add(name, "unchanged")
+				continue
+			}
+		}
+		if !opts.DryRun {
+			full, err := GetContent(src, c)
+			if err != nil {
+				return err
+			}
+			err = dst.CreateConfig(&nacos.CreateCfgOpts{
+				DataID:      full.DataID,
+				Group:       full.GetGroup(),
+				NamespaceID: dstNs,
+				Content:     full.Content,
+				Type:        full.Type,
+				Description: full.Description,
+				Application: full.Application,
+				Tags:        full.Tags,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		report.Add(name, action(createdOrUpdated(exists)))
+	}
+	if !opts.Delete {
+		return nil
+	}
+	for _, key := range slices.Sorted(maps.Keys(dstCs)) {
+		d := dstCs[key]
+		if !opts.DryRun {
+			if err := dst.DeleteConfig(&nacos.DeleteCfgOpts{DataID: d.DataID, Group: d.GetGroup(), NamespaceID: dstNs}); err != nil {
+				return err
+			}
+		}
+		report.Add(fmt.Sprintf("configuration/%s/%s", namespace, key), action("deleted"))
+	}
+	return nil
+}
+
+// sameConfig compares the md5 and type of two configurations, the content
+// is fetched when the list api did not return the md5.
+func sameConfig(src, dst *nacos.Client, a, b *nacos.Configuration) (bool, error) {
+	md5 := func(client *nacos.Client, c *nacos.Configuration) (string, error) {
+		if sum := nacos.ContentMd5(c); sum != "" {
+			return sum, nil
+		}
+		full, err := GetContent(client, c)
+		if err != nil {
+			return "", err
+		}
+		return nacos.ContentMd5(full), nil
+	}
+	sumA, err := md5(src, a)
+	if err != nil {
+		return false, err
+	}
+	sumB, err := md5(dst, b)
+	if err != nil {
+		return false, err
+	}
+	return sumA == sumB && a.Type == b.Type, nil
+}
+
+func createdOrUpdated(exists bool) string {
+	if exists {
+		return "updated"
+	}
+	return "created"
+}
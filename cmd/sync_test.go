@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/joelee2012/nacosctl/pkg/nacos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncV1ToV3(t *testing.T) {
+	src := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/console/server/state":
+			w.Write([]byte(`{"version": "2.4.0"}`))
+		case "/v1/auth/login":
+			w.Write([]byte(`{"accessToken": "test-token", "tokenTtl": 3600}`))
+		case "/v1/console/namespaces":
+			w.Write([]byte(`{"code": 200, "data": [{"namespace": "", "namespaceShowName": "public"}]}`))
+		case "/v1/cs/configs":
+			w.Write([]byte(`{"totalCount": 1, "pageNumber": 1, "pagesAvailable": 1, "pageItems": [{"dataId": "data1", "group": "DEFAULT_GROUP", "content": "a=1", "tenant": ""}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer src.Close()
+	var created []url.Values
+	var nsCreated int
+	dst := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/console/server/state":
+			w.Write([]byte(`{"version": "3.0.0"}`))
+		case "/v3/auth/user/login":
+			w.Write([]byte(`{"accessToken": "test-token", "tokenTtl": 3600}`))
+		case "/v3/console/core/namespace/list":
+			w.Write([]byte(`{"code": 0, "data": [{"namespace": "public", "namespaceShowName": "public"}]}`))
+		case "/v3/console/core/namespace":
+			nsCreated++
+			w.Write([]byte(`{"code": 0, "data": true}`))
+		case "/v3/console/cs/config/list":
+			w.Write([]byte(`{"code": 0, "data": {"totalCount": 0, "pageNumber": 1, "pagesAvailable": 0, "pageItems": []}}`))
+		case "/v3/console/cs/config":
+			r.ParseForm()
+			created = append(created, r.PostForm)
+			w.Write([]byte(`{"code": 0, "data": true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer dst.Close()
+
+	srcClient := nacos.NewClient(src.URL, "user", "password")
+	dstClient := nacos.NewClient(dst.URL, "user", "password")
+	assert.Equal(t, "v1", srcClient.APIVersion)
+	assert.Equal(t, "v3", dstClient.APIVersion)
+	report, err := Sync(srcClient, dstClient, &SyncOpts{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, Report{
+			{Name: "namespace/public", Action: "unchanged"},
+			{Name: "configuration/public/DEFAULT_GROUP/data1", Action: "created"},
+		}, report)
+	}
+	assert.Equal(t, 0, nsCreated)
+	if assert.Len(t, created, 1) {
+		assert.Equal(t, "public", created[0].Get("namespaceId"))
+		assert.Equal(t, "a=1", created[0].Get("content"))
+	}
+}
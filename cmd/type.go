@@ -17,10 +17,15 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/goccy/go-yaml"
 	"github.com/jedib0t/go-pretty/table"
@@ -53,6 +58,8 @@ type Configuration struct {
 		Application string `json:"application,omitempty"`
 		Description string `json:"description,omitempty"`
 		Tags        string `json:"tags,omitempty"`
+		// Beta stages a gray release of the configuration to some clients
+		Beta *ConfigurationBeta `json:"beta,omitempty"`
 	} `json:"spec"`
 	Status struct {
 		Md5              string `json:"md5,omitempty"`
@@ -81,6 +88,20 @@ func NewConfiguration(apiVersion string, cfg *nacos.Configuration) *Configuratio
 	return c
 }
 
+type ConfigurationBeta struct {
+	Ips     []string `json:"ips"`
+	Content string   `json:"data,omitempty"`
+}
+
+// NewBetaConfiguration returns the beta release as a Configuration whose
+// spec.data is left empty and spec.beta holds the gray content.
+func NewBetaConfiguration(apiVersion string, beta *nacos.BetaConfiguration) *Configuration {
+	c := NewConfiguration(apiVersion, &beta.Configuration)
+	c.Spec.Content = ""
+	c.Spec.Beta = &ConfigurationBeta{Ips: strings.Split(beta.BetaIps, ","), Content: beta.Content}
+	return c
+}
+
 func (c Configuration) TableHeader() table.Row {
 	return table.Row{"NAMESPACEID", "DATAID", "GROUP", "APPLICATION", "TYPE"}
 }
@@ -98,6 +119,65 @@ func (c Configuration) WriteToDir(base string) error {
 	return writeYamlFile(c, filepath.Join(dir, c.Metadata.DataID))
 }
 
+type ConfigHistory struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		ID        string `json:"id"`
+		Group     string `json:"group"`
+		DataID    string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Content     string `json:"data,omitempty"`
+		Application string `json:"application,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		LastID     string `json:"lastId,omitempty"`
+		Md5        string `json:"md5,omitempty"`
+		OpType     string `json:"opType,omitempty"`
+		SrcIP      string `json:"srcIp,omitempty"`
+		SrcUser    string `json:"srcUser,omitempty"`
+		ModifyTime string `json:"modifyTime,omitempty"`
+	} `json:"status"`
+}
+
+func NewConfigHistory(apiVersion string, h *nacos.ConfigHistory) *ConfigHistory {
+	c := new(ConfigHistory)
+	c.APIVersion = apiVersion
+	c.Kind = "ConfigHistory"
+	c.Metadata.ID = h.ID.String()
+	c.Metadata.DataID = h.DataID
+	c.Metadata.Namespace = h.GetNamespace()
+	c.Metadata.Group = h.GetGroup()
+	c.Spec.Content = h.Content
+	c.Spec.Application = h.Application
+	c.Status.LastID = h.LastID.String()
+	c.Status.Md5 = h.Md5
+	c.Status.OpType = strings.TrimSpace(h.OpType)
+	c.Status.SrcIP = h.SrcIP
+	c.Status.SrcUser = h.SrcUser
+	c.Status.ModifyTime = h.GetModifyTime().String()
+	return c
+}
+
+func (h ConfigHistory) TableHeader() table.Row {
+	return table.Row{"HISTORYID", "DATAID", "GROUP", "OPERATION", "USER", "SOURCEIP", "MODIFYTIME"}
+}
+
+func (h ConfigHistory) TableRow() table.Row {
+	return table.Row{h.Metadata.ID, h.Metadata.DataID, h.Metadata.Group, h.Status.OpType,
+		h.Status.SrcUser, h.Status.SrcIP, h.Status.ModifyTime}
+}
+
+func (h ConfigHistory) WriteToDir(base string) error {
+	dir := filepath.Join(base, h.Metadata.Namespace, h.Metadata.Group, h.Metadata.DataID+".history")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	return writeYamlFile(h, filepath.Join(dir, h.Metadata.ID))
+}
+
 type Namespace struct {
 	APIVersion string `json:"apiVersion"`
 	Kind       string `json:"kind"`
@@ -139,13 +219,209 @@ func (n Namespace) WriteToDir(base string) error {
 	return writeYamlFile(n, filepath.Join(base, n.Metadata.ID+".yaml"))
 }
 
+type Service struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Group     string `json:"group"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		ProtectThreshold float64           `json:"protectThreshold"`
+		Metadata         map[string]string `json:"metadata,omitempty"`
+		Selector         *nacos.Selector   `json:"selector,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		ClusterCount         int `json:"clusterCount,omitempty"`
+		IpCount              int `json:"ipCount,omitempty"`
+		HealthyInstanceCount int `json:"healthyInstanceCount,omitempty"`
+	} `json:"status"`
+}
+
+func NewService(apiVersion string, svc *nacos.Service) *Service {
+	s := new(Service)
+	s.APIVersion = apiVersion
+	s.Kind = "Service"
+	s.Metadata.Name = svc.GetName()
+	s.Metadata.Group = svc.GroupName
+	s.Metadata.Namespace = svc.NamespaceID
+	s.Spec.ProtectThreshold = svc.ProtectThreshold
+	s.Spec.Metadata = svc.Metadata
+	// "none" is the default selector, leave it out of the manifest
+	if svc.Selector != nil && svc.Selector.Type != "none" {
+		s.Spec.Selector = svc.Selector
+	}
+	s.Status.ClusterCount = svc.ClusterCount
+	s.Status.IpCount = svc.IpCount
+	s.Status.HealthyInstanceCount = svc.HealthyInstanceCount
+	return s
+}
+
+func (s Service) TableHeader() table.Row {
+	return table.Row{"NAME", "GROUP", "CLUSTERS", "INSTANCES", "HEALTHY", "THRESHOLD"}
+}
+func (s Service) TableRow() table.Row {
+	return table.Row{s.Metadata.Name, s.Metadata.Group, s.Status.ClusterCount,
+		s.Status.IpCount, s.Status.HealthyInstanceCount, s.Spec.ProtectThreshold}
+}
+func (s Service) WriteToDir(base string) error {
+	dir := filepath.Join(base, s.Metadata.Namespace, s.Metadata.Group)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	return writeYamlFile(s, filepath.Join(dir, s.Metadata.Name+".service.yaml"))
+}
+
+type Instance struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Service   string `json:"service"`
+		Group     string `json:"group"`
+		Namespace string `json:"namespace"`
+		Cluster   string `json:"cluster"`
+	} `json:"metadata"`
+	Spec struct {
+		IP        string            `json:"ip"`
+		Port      int               `json:"port"`
+		Weight    float64           `json:"weight"`
+		Enabled   bool              `json:"enabled"`
+		Ephemeral bool              `json:"ephemeral"`
+		Metadata  map[string]string `json:"metadata,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		InstanceID string `json:"instanceId,omitempty"`
+		Healthy    bool   `json:"healthy"`
+	} `json:"status"`
+}
+
+func NewInstance(apiVersion string, inst *nacos.Instance) *Instance {
+	i := new(Instance)
+	i.APIVersion = apiVersion
+	i.Kind = "Instance"
+	i.Metadata.Service = inst.GetServiceName()
+	i.Metadata.Group = inst.GroupName
+	i.Metadata.Namespace = inst.NamespaceID
+	i.Metadata.Cluster = inst.ClusterName
+	i.Spec.IP = inst.IP
+	i.Spec.Port = inst.Port
+	i.Spec.Weight = inst.Weight
+	i.Spec.Enabled = inst.Enabled
+	i.Spec.Ephemeral = inst.Ephemeral
+	i.Spec.Metadata = inst.Metadata
+	i.Status.InstanceID = inst.InstanceID
+	i.Status.Healthy = inst.Healthy
+	return i
+}
+
+// Address returns the instance as ip:port.
+func (i Instance) Address() string {
+	return net.JoinHostPort(i.Spec.IP, strconv.Itoa(i.Spec.Port))
+}
+
+func (i Instance) TableHeader() table.Row {
+	return table.Row{"NAME", "CLUSTER", "HEALTHY", "ENABLED", "WEIGHT", "EPHEMERAL", "METADATA"}
+}
+func (i Instance) TableRow() table.Row {
+	var md []string
+	for k, v := range i.Spec.Metadata {
+		md = append(md, k+"="+v)
+	}
+	slices.Sort(md)
+	return table.Row{i.Address(), i.Metadata.Cluster, i.Status.Healthy, i.Spec.Enabled,
+		i.Spec.Weight, i.Spec.Ephemeral, strings.Join(md, ",")}
+}
+func (i Instance) WriteToDir(base string) error {
+	dir := filepath.Join(base, i.Metadata.Namespace, i.Metadata.Group, i.Metadata.Service+".instances")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	return writeYamlFile(i, filepath.Join(dir, fmt.Sprintf("%s_%d.yaml", i.Spec.IP, i.Spec.Port)))
+}
+
+type Node struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Status struct {
+		State           string               `json:"state"`
+		RaftRole        string               `json:"raftRole,omitempty"`
+		LastRefreshTime string               `json:"lastRefreshTime,omitempty"`
+		FailAccessCnt   int                  `json:"failAccessCnt,omitempty"`
+		ExtendInfo      nacos.NodeExtendInfo `json:"extendInfo"`
+	} `json:"status"`
+}
+
+func NewNode(apiVersion string, node *nacos.Node) *Node {
+	n := new(Node)
+	n.APIVersion = apiVersion
+	n.Kind = "Node"
+	n.Metadata.Name = node.Address
+	n.Status.State = node.State
+	n.Status.RaftRole = node.RaftRole()
+	n.Status.LastRefreshTime = node.ExtendInfo.LastRefreshTime.String()
+	n.Status.FailAccessCnt = node.FailAccessCnt
+	n.Status.ExtendInfo = node.ExtendInfo
+	return n
+}
+
+func (n Node) TableHeader() table.Row {
+	return table.Row{"NAME", "STATE", "RAFTROLE", "LASTREFRESHTIME", "VERSION", "FAILACCESSCNT"}
+}
+func (n Node) TableRow() table.Row {
+	return table.Row{n.Metadata.Name, n.Status.State, n.Status.RaftRole, n.Status.LastRefreshTime,
+		n.Status.ExtendInfo.Version, n.Status.FailAccessCnt}
+}
+func (n Node) WriteToDir(base string) error {
+	if err := os.MkdirAll(base, 0750); err != nil {
+		return err
+	}
+	return writeYamlFile(n, filepath.Join(base, strings.ReplaceAll(n.Metadata.Name, ":", "_")+".yaml"))
+}
+
 type User struct {
 	APIVersion string `json:"apiVersion"`
 	Kind       string `json:"kind"`
 	Metadata   struct {
-		Name     string `json:"username"`
+		Name string `json:"username"`
+		// Password is the hash stored by nacos, it is never sent back
 		Password string `json:"password"`
 	} `json:"metadata"`
+	Spec struct {
+		// PasswordFrom is where apply reads the password of the user
+		PasswordFrom *SecretRef `json:"passwordFrom,omitempty"`
+	} `json:"spec"`
+}
+
+// SecretRef tells where to read a secret from, so that manifests and
+// command lines never hold it in plain text.
+type SecretRef struct {
+	Env  string `json:"env,omitempty"`
+	File string `json:"file,omitempty"`
+}
+
+// Resolve returns the secret, read from the environment variable or the
+// file, without the trailing newline of the file.
+func (r *SecretRef) Resolve() (string, error) {
+	switch {
+	case r.Env != "":
+		v, ok := os.LookupEnv(r.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", r.Env)
+		}
+		return v, nil
+	case r.File != "":
+		data, err := os.ReadFile(r.File)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return "", errors.New("secret reference needs an env or a file")
+	}
 }
 
 func NewUser(apiVersion string, user *nacos.User) *User {
@@ -225,6 +501,32 @@ func (p Permission) WriteToDir(base string) error {
 	return writeYamlFile(p, filepath.Join(base, p.Metadata.Role+p.Metadata.Resource+p.Metadata.Action+".yaml"))
 }
 
+// Result records what a bulk command did to one object.
+type Result struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// Report lists the results of a bulk command such as import or clone.
+type Report []Result
+
+func (r *Report) Add(name, action string) {
+	*r = append(*r, Result{Name: name, Action: action})
+}
+
+func (r Report) ToTable(w io.Writer) {
+	if len(r) == 0 {
+		w.Write([]byte("No resources found"))
+		return
+	}
+	tb := newTable(w)
+	tb.AppendHeader(table.Row{"NAME", "RESULT"})
+	for _, it := range r {
+		tb.AppendRow(table.Row{it.Name, it.Action})
+	}
+	tb.Render()
+}
+
 func toJson(v any, w io.Writer) error {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
@@ -274,6 +576,37 @@ func WriteFormat(fw FormatWriter, format string, w io.Writer) error {
 	return nil
 }
 
+// WriteEvent writes one object of a watch stream, as a table row, a json
+// line or a yaml document. The table header is written only when header is true.
+func WriteEvent(row TableRow, format string, header bool, w io.Writer) error {
+	switch format {
+	case "json":
+		return json.NewEncoder(w).Encode(row)
+	case "table":
+		tb := newTable(w)
+		if header {
+			tb.AppendHeader(row.TableHeader())
+		}
+		tb.AppendRow(row.TableRow())
+		tb.Render()
+		return nil
+	default:
+		if _, err := io.WriteString(w, "---\n"); err != nil {
+			return err
+		}
+		return toYaml(row, w)
+	}
+}
+
+func newTable(w io.Writer) table.Writer {
+	tb := table.NewWriter()
+	tb.SetOutputMirror(w)
+	s := table.StyleLight
+	s.Options = table.OptionsNoBordersAndSeparators
+	tb.SetStyle(s)
+	return tb
+}
+
 type ListTypes interface {
 	TableRow
 	DirWriter
@@ -286,9 +619,13 @@ type List[T ListTypes] struct {
 }
 
 type ConfigurationList = List[Configuration]
+type ConfigHistoryList = List[ConfigHistory]
+type InstanceList = List[Instance]
 type NamespaceList = List[Namespace]
+type NodeList = List[Node]
 type PermissionList = List[Permission]
 type RoleList = List[Role]
+type ServiceList = List[Service]
 type UserList = List[User]
 
 func NewList[T ListTypes, S any](apiVersion string, items []S, covert func(apiVersion string, s S) *T) *List[T] {
@@ -302,23 +639,24 @@ func NewList[T ListTypes, S any](apiVersion string, items []S, covert func(apiVe
 }
 
 func (lst *List[T]) ToTable(w io.Writer) {
-	tb := table.NewWriter()
-	tb.SetOutputMirror(w)
 	if len(lst.Items) == 0 {
 		w.Write([]byte("No resources found"))
 		return
 	}
+	tb := newTable(w)
 	tb.AppendHeader(lst.Items[0].TableHeader())
 	for _, it := range lst.Items {
 		tb.AppendRow(it.TableRow())
 	}
 	tb.SortBy([]table.SortBy{{Name: "NAME", Mode: table.Asc}, {Name: "ID", Mode: table.Asc}})
-	s := table.StyleLight
-	s.Options = table.OptionsNoBordersAndSeparators
-	tb.SetStyle(s)
 	tb.Render()
 }
 
+// Filter keeps only the items for which keep returns true.
+func (lst *List[T]) Filter(keep func(T) bool) {
+	lst.Items = slices.DeleteFunc(lst.Items, func(it T) bool { return !keep(it) })
+}
+
 func (lst *List[T]) WriteToDir(base string) error {
 	for _, it := range lst.Items {
 		if err := it.WriteToDir(base); err != nil {
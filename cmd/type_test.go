@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -235,3 +236,180 @@ func TestUserListWriteTable(t *testing.T) {
 		assert.Equal(t, "No resources found", buf.String())
 	})
 }
+
+func TestConfigHistoryList(t *testing.T) {
+	hs := []*nacos.ConfigHistory{
+		{ID: "2", DataID: "data1", Group: "group1", Tenant: "ns1", OpType: "U         ", SrcUser: "nacos", Content: "a=1"},
+	}
+	hl := NewList(apiVersion, hs, NewConfigHistory)
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		hl.ToTable(&buf)
+		output := buf.String()
+		assert.Contains(t, output, "HISTORYID")
+		assert.Contains(t, output, "nacos")
+		assert.Equal(t, "U", hl.Items[0].Status.OpType)
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		assert.NoError(t, hl.WriteToDir(tmpDir))
+		assert.FileExists(t, filepath.Join(tmpDir, "ns1", "group1", "data1.history", "2"))
+	})
+}
+
+func TestWriteEvent(t *testing.T) {
+	c := NewConfiguration(apiVersion, cs[0])
+	tests := []struct {
+		format  string
+		header  bool
+		want    string
+		notWant string
+	}{
+		{"table", true, "NAMESPACEID", ""},
+		{"table", false, "data1", "NAMESPACEID"},
+		{"json", false, `{"apiVersion":"v1","kind":"Configuration"`, "\n  "},
+		{"yaml", false, "---\napiVersion: v1\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, WriteEvent(c, tt.format, tt.header, &buf))
+			assert.Contains(t, buf.String(), tt.want)
+			if tt.notWant != "" {
+				assert.NotContains(t, buf.String(), tt.notWant)
+			}
+		})
+	}
+}
+
+func TestNewBetaConfiguration(t *testing.T) {
+	beta := &nacos.BetaConfiguration{
+		Configuration: nacos.Configuration{NamespaceID: "ns1", DataID: "data1", Group: "group1", Content: "beta"},
+		BetaIps:       "127.0.0.1,127.0.0.2",
+	}
+	c := NewBetaConfiguration(apiVersion, beta)
+	assert.Equal(t, "", c.Spec.Content)
+	assert.Equal(t, &ConfigurationBeta{Ips: []string{"127.0.0.1", "127.0.0.2"}, Content: "beta"}, c.Spec.Beta)
+}
+
+func TestServiceList(t *testing.T) {
+	svcs := []*nacos.Service{
+		{Name: "group1@@svc1", GroupName: "group1", NamespaceID: "ns1", ProtectThreshold: 0.5, IpCount: 2, HealthyInstanceCount: 1},
+	}
+	sl := NewList(apiVersion, svcs, NewService)
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		sl.ToTable(&buf)
+		output := buf.String()
+		assert.Contains(t, output, "THRESHOLD")
+		assert.Contains(t, output, "svc1")
+		assert.NotContains(t, output, "@@")
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		assert.NoError(t, sl.WriteToDir(tmpDir))
+		assert.FileExists(t, filepath.Join(tmpDir, "ns1", "group1", "svc1.service.yaml"))
+	})
+
+	t.Run("selector", func(t *testing.T) {
+		svc := &nacos.Service{Name: "svc1", Selector: &nacos.Selector{Type: "none"}}
+		assert.Nil(t, NewService(apiVersion, svc).Spec.Selector)
+		svc.Selector = &nacos.Selector{Type: "label", Expression: "a = b"}
+		assert.Equal(t, svc.Selector, NewService(apiVersion, svc).Spec.Selector)
+	})
+}
+
+func TestInstanceList(t *testing.T) {
+	insts := []*nacos.Instance{
+		{IP: "10.0.0.1", Port: 8080, Weight: 1, Healthy: true, Enabled: true, ClusterName: "DEFAULT", ServiceName: "group1@@svc1", GroupName: "group1", NamespaceID: "ns1", Metadata: map[string]string{"b": "2", "a": "1"}},
+		{IP: "10.0.0.2", Port: 8080, Weight: 1, Healthy: false, Enabled: true, ClusterName: "DEFAULT", ServiceName: "group1@@svc1", GroupName: "group1", NamespaceID: "ns1"},
+		{IP: "10.0.0.3", Port: 8080, Weight: 1, Healthy: false, Enabled: false, ClusterName: "DEFAULT", ServiceName: "group1@@svc1", GroupName: "group1", NamespaceID: "ns1"},
+	}
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		NewList(apiVersion, insts, NewInstance).ToTable(&buf)
+		output := buf.String()
+		assert.Contains(t, output, "10.0.0.1:8080")
+		assert.Contains(t, output, "a=1,b=2")
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		assert.NoError(t, NewList(apiVersion, insts, NewInstance).WriteToDir(tmpDir))
+		assert.FileExists(t, filepath.Join(tmpDir, "ns1", "group1", "svc1.instances", "10.0.0.1_8080.yaml"))
+	})
+
+	tests := []struct {
+		name string
+		opts InstanceOpts
+		want []string
+	}{
+		{"all", InstanceOpts{}, []string{"10.0.0.1:8080", "10.0.0.2:8080", "10.0.0.3:8080"}},
+		{"unhealthy", InstanceOpts{Unhealthy: true}, []string{"10.0.0.2:8080", "10.0.0.3:8080"}},
+		{"unhealthy and disabled", InstanceOpts{Unhealthy: true, Disabled: true}, []string{"10.0.0.3:8080"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := NewList(apiVersion, insts, NewInstance)
+			FilterInstances(list, &tt.opts)
+			var got []string
+			for _, i := range list.Items {
+				got = append(got, i.Address())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNodeList(t *testing.T) {
+	nodes := []*nacos.Node{{IP: "10.0.0.1", Port: 8848, Address: "10.0.0.1:8848", State: "DOWN"}}
+	nodes[0].ExtendInfo.Version = "2.4.0"
+	nodes[0].ExtendInfo.LastRefreshTime = "1700000000000"
+	nl := NewList(apiVersion, nodes, NewNode)
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		nl.ToTable(&buf)
+		output := buf.String()
+		assert.Contains(t, output, "RAFTROLE")
+		assert.Contains(t, output, "DOWN")
+		assert.Contains(t, output, "2.4.0")
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		assert.NoError(t, nl.WriteToDir(tmpDir))
+		assert.FileExists(t, filepath.Join(tmpDir, "10.0.0.1_8848.yaml"))
+	})
+}
+
+func TestSecretRefResolve(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "password")
+	assert.NoError(t, os.WriteFile(name, []byte("from-file\n"), 0600))
+	t.Setenv("NCTL_TEST_PASSWORD", "from-env")
+	tests := []struct {
+		name string
+		ref  SecretRef
+		want string
+		err  string
+	}{
+		{"env", SecretRef{Env: "NCTL_TEST_PASSWORD"}, "from-env", ""},
+		{"file", SecretRef{File: name}, "from-file", ""},
+		{"unset env", SecretRef{Env: "NCTL_TEST_UNSET"}, "", "NCTL_TEST_UNSET is not set"},
+		{"missing file", SecretRef{File: name + ".missing"}, "", "no such file"},
+		{"empty", SecretRef{}, "", "needs an env or a file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ref.Resolve()
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
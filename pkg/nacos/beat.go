@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// beatNotFound is the code of a beat for an instance the server no longer
+// knows, the instance has to be registered again.
+const beatNotFound = 20404
+
+type BeatResult struct {
+	ClientBeatInterval int64 `json:"clientBeatInterval"`
+	Code               int   `json:"code"`
+	LightBeatEnabled   bool  `json:"lightBeatEnabled"`
+}
+
+type beatInfo struct {
+	ServiceName string            `json:"serviceName"`
+	IP          string            `json:"ip"`
+	Port        int               `json:"port"`
+	Cluster     string            `json:"cluster"`
+	Weight      float64           `json:"weight"`
+	Metadata    map[string]string `json:"metadata"`
+	Scheduled   bool              `json:"scheduled"`
+}
+
+// SendBeat renews the lease of an ephemeral instance, a light beat only
+// carries the address of the instance.
+func (c *Client) SendBeat(inst *Instance, light bool) (*BeatResult, error) {
+	return c.SendBeatCtx(c.context(), inst, light)
+}
+
+func (c *Client) SendBeatCtx(ctx context.Context, inst *Instance, light bool) (*BeatResult, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	serviceName := inst.GroupName + "@@" + inst.GetServiceName()
+	v := url.Values{}
+	v.Add("namespaceId", inst.NamespaceID)
+	v.Add("groupName", inst.GroupName)
+	v.Add("serviceName", serviceName)
+	v.Add("clusterName", inst.ClusterName)
+	v.Add("ip", inst.IP)
+	v.Add("port", strconv.Itoa(inst.Port))
+	v.Add("accessToken", token)
+	if !light {
+		beat, err := json.Marshal(&beatInfo{
+			ServiceName: serviceName,
+			IP:          inst.IP,
+			Port:        inst.Port,
+			Cluster:     inst.ClusterName,
+			Weight:      inst.Weight,
+			Metadata:    inst.Metadata,
+		})
+		if err != nil {
+			return nil, err
+		}
+		v.Add("beat", string(beat))
+	}
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["beat"], v.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	result := new(BeatResult)
+	return result, decode(resp, err, result)
+}
+
+// KeepAlive keeps an ephemeral instance registered by sending beats at the
+// interval asked by the server.
+type KeepAlive struct {
+	client *Client
+	inst   *Instance
+	// Interval is the time between two beats until the server returns its own.
+	Interval time.Duration
+	// OnBeat, if not nil, is called after every beat. A failed beat is not
+	// fatal, it is retried at the next interval.
+	OnBeat func(*BeatResult, error)
+}
+
+func (c *Client) NewKeepAlive(inst *Instance) *KeepAlive {
+	return &KeepAlive{client: c, inst: inst, Interval: 5 * time.Second}
+}
+
+// Run registers the instance and sends beats until stop is closed, then it
+// deregisters the instance.
+func (k *KeepAlive) Run(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(k.client.context())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return k.RunCtx(ctx)
+}
+
+// RunCtx is Run until ctx is done, the instance is deregistered with a
+// context which is not canceled along with ctx.
+func (k *KeepAlive) RunCtx(ctx context.Context) error {
+	k.inst.Ephemeral = true
+	if err := k.client.RegisterInstanceCtx(ctx, k.inst); err != nil {
+		return err
+	}
+	light := false
+	timer := time.NewTimer(k.Interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return k.client.DeregisterInstanceCtx(context.WithoutCancel(ctx), k.inst)
+		case <-timer.C:
+		}
+		result, err := k.client.SendBeatCtx(ctx, k.inst, light)
+		if err == nil {
+			if result.ClientBeatInterval > 0 {
+				k.Interval = time.Duration(result.ClientBeatInterval) * time.Millisecond
+			}
+			light = result.LightBeatEnabled
+			if result.Code == beatNotFound {
+				err = k.client.RegisterInstanceCtx(ctx, k.inst)
+			}
+		}
+		if k.OnBeat != nil && ctx.Err() == nil {
+			k.OnBeat(result, err)
+		}
+		timer.Reset(k.Interval)
+	}
+}
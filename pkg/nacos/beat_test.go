@@ -0,0 +1,57 @@
+package nacos
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepAlive(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	var beats []string
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/ns/instance":
+			calls[r.Method]++
+			w.Write([]byte("ok"))
+		case "/v1/ns/instance/beat":
+			beats = append(beats, r.URL.Query().Get("beat"))
+			if len(beats) == 1 {
+				w.Write([]byte(`{"clientBeatInterval": 10, "code": 20404, "lightBeatEnabled": true}`))
+			} else {
+				w.Write([]byte(`{"clientBeatInterval": 10, "code": 10200, "lightBeatEnabled": true}`))
+			}
+		}
+	})
+	defer ts.Close()
+
+	inst := &Instance{IP: "10.0.0.1", Port: 8080, Weight: 1, Enabled: true, ServiceName: "svc1", GroupName: "DEFAULT_GROUP"}
+	ka := c.NewKeepAlive(inst)
+	ka.Interval = time.Millisecond
+	stop := make(chan struct{})
+	n := 0
+	ka.OnBeat = func(result *BeatResult, err error) {
+		assert.NoError(t, err)
+		if n++; n == 3 {
+			close(stop)
+		}
+	}
+	assert.NoError(t, ka.Run(stop))
+	assert.True(t, inst.Ephemeral)
+	assert.Equal(t, 10*time.Millisecond, ka.Interval)
+	mu.Lock()
+	defer mu.Unlock()
+	// registered at start and again after the 20404 beat
+	assert.Equal(t, 2, calls[http.MethodPost])
+	assert.Equal(t, 1, calls[http.MethodDelete])
+	if assert.Len(t, beats, 3) {
+		assert.Contains(t, beats[0], `"serviceName":"DEFAULT_GROUP@@svc1"`)
+		assert.Equal(t, "", beats[1])
+	}
+}
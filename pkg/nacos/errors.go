@@ -0,0 +1,123 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nacos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// codeResourceNotFound is the code of the v3 api for a missing object.
+const codeResourceNotFound = 20004
+
+// NacosErr is an error answered by the server, or a missing object the
+// server answered with an empty response.
+type NacosErr struct {
+	// StatusCode is the http status of the response
+	StatusCode int
+	// URL is the url of the request without its query, or the name of the
+	// missing object
+	URL string
+	// Code is the code of a {code, message, data} response, 0 if there is none
+	Code int
+	// Message is the message of the response, its body if it is not json
+	Message string
+	Err     error
+}
+
+func (e *NacosErr) Error() string {
+	msg := fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	for _, s := range []string{e.URL, e.Message} {
+		if s != "" {
+			msg += " " + s
+		}
+	}
+	if e.Err != nil {
+		msg += " " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *NacosErr) Unwrap() error { return e.Err }
+
+// newNacosErr reads the error of a response whose status is not 200.
+func newNacosErr(resp *http.Response, data []byte, err error) *NacosErr {
+	e := &NacosErr{StatusCode: resp.StatusCode, URL: redact(resp.Request.URL), Err: err}
+	// no data or html data
+	if len(data) == 0 || data[0] == '<' {
+		return e
+	}
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &result) == nil && result.Message != "" {
+		e.Code, e.Message = result.Code, result.Message
+	} else {
+		e.Message = string(data)
+	}
+	return e
+}
+
+func notFound(name string, err error) *NacosErr {
+	return &NacosErr{StatusCode: http.StatusNotFound, URL: name, Err: err}
+}
+
+// IsNotFound reports whether err tells that the object does not exist.
+func IsNotFound(err error) bool {
+	var e *NacosErr
+	return errors.As(err, &e) && (e.StatusCode == http.StatusNotFound || e.Code == codeResourceNotFound)
+}
+
+// IsUnauthorized reports whether the server refused the credentials or the
+// access to the object, nacos answers both with 403.
+func IsUnauthorized(err error) bool {
+	var e *NacosErr
+	return errors.As(err, &e) && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden)
+}
+
+// IsConflict reports whether err is a *ConflictError, or the server
+// rejecting a change because the object was modified or already exists.
+func IsConflict(err error) bool {
+	var conflict *ConflictError
+	if errors.As(err, &conflict) {
+		return true
+	}
+	var e *NacosErr
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.StatusCode == http.StatusConflict || strings.Contains(e.Message, "Cas publish fail") || strings.Contains(e.Message, "md5 may have changed")
+}
+
+// ConflictError is returned by CreateConfig when the configuration on the
+// server no longer has the md5 given in CasMd5.
+type ConflictError struct {
+	DataID      string
+	Group       string
+	NamespaceID string
+	Md5         string
+	Err         error
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("configuration %s/%s/%s was modified since md5 %s: %s", e.NamespaceID, e.Group, e.DataID, e.Md5, e.Err)
+}
+
+func (e *ConflictError) Unwrap() error { return e.Err }
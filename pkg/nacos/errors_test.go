@@ -0,0 +1,83 @@
+package nacos
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNacosErr(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<html><body>404 Not Found</body></html>`))
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code": 403, "message": "authorization failed!", "data": null}`))
+		case "/resource":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code": 20004, "message": "resource not found", "data": null}`))
+		case "/conflict":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`caused: Cas publish fail`))
+		}
+	}))
+	defer ts.Close()
+
+	get := func(path string) error {
+		resp, err := http.Get(ts.URL + path + "?accessToken=secret")
+		return checkErr(resp, err)
+	}
+	tests := []struct {
+		path                          string
+		notFound, unauthorized, confl bool
+		code                          int
+		message                       string
+		errString                     string
+	}{
+		{"/missing", true, false, false, 0, "", "404 Not Found " + ts.URL + "/missing"},
+		{"/forbidden", false, true, false, 403, "authorization failed!", "403 Forbidden " + ts.URL + "/forbidden authorization failed!"},
+		{"/resource", true, false, false, 20004, "resource not found", ""},
+		{"/conflict", false, false, true, 0, "caused: Cas publish fail", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := get(tt.path)
+			var e *NacosErr
+			if assert.ErrorAs(t, err, &e) {
+				assert.Equal(t, tt.code, e.Code)
+				assert.Equal(t, tt.message, e.Message)
+				assert.NotContains(t, err.Error(), "secret")
+			}
+			if tt.errString != "" {
+				assert.EqualError(t, err, tt.errString)
+			}
+			wrapped := fmt.Errorf("wrapped: %w", err)
+			assert.Equal(t, tt.notFound, IsNotFound(wrapped))
+			assert.Equal(t, tt.unauthorized, IsUnauthorized(wrapped))
+			assert.Equal(t, tt.confl, IsConflict(wrapped))
+		})
+	}
+
+	assert.NoError(t, get("/ok"))
+	assert.True(t, IsConflict(&ConflictError{Err: errors.New("md5 changed")}))
+	for _, err := range []error{nil, errors.New("404 Not Found test"), io.EOF} {
+		assert.False(t, IsNotFound(err))
+		assert.False(t, IsUnauthorized(err))
+		assert.False(t, IsConflict(err))
+	}
+}
+
+func TestGetUserNotFound(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	_, err := c.GetUser("missing")
+	assert.True(t, IsNotFound(err))
+	assert.EqualError(t, err, "404 Not Found user missing")
+}
@@ -16,21 +16,46 @@ limitations under the License.
 package nacos
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Client sends requests to a nacos server. Every method that sends requests
+// has a Ctx variant which takes the context of the requests, the method
+// without it uses the context given to WithContext, or context.Background.
 type Client struct {
 	URL        string
 	User       string
 	Password   string
 	APIVersion string
+	// PasswordFunc, if not nil, gives the password on the first login
+	// that needs it when Password is empty, e.g. read from a secret store
+	PasswordFunc func() (string, error)
+	// TokenStore, if not nil, keeps the token between clients
+	TokenStore TokenStore
+	// URLs are all the nodes of the cluster, URL is the one requests are
+	// sent to, it moves to the next one when the node can't be reached
+	URLs []string
+	// RetryPolicy is DefaultRetryPolicy when nil
+	RetryPolicy *RetryPolicy
+	// Logger, if not nil, logs the retries of failed requests
+	Logger     Logger
+	httpClient *http.Client
+	prevURL    string
+	// storedToken tells that Token was loaded from TokenStore, the server
+	// may have revoked it since it was saved
+	storedToken bool
+	ctx         context.Context
 	*Token
 	*State
 }
@@ -39,13 +64,18 @@ type Token struct {
 	TokenTTL    int64  `json:"tokenTtl"`
 	GlobalAdmin bool   `json:"globalAdmin"`
 	Username    string `json:"username"`
-	ExpiredAt   int64
+	ExpiredAt   int64  `json:"expiredAt"`
 }
 
 func (t *Token) Expired() bool {
 	return time.Now().After(time.Unix(t.ExpiredAt, 0))
 }
 
+// ExpiresWithin reports whether the token expires in less than d.
+func (t *Token) ExpiresWithin(d time.Duration) bool {
+	return time.Now().Add(d).After(time.Unix(t.ExpiredAt, 0))
+}
+
 type State struct {
 	Version        string `json:"version"`
 	StandaloneMode string `json:"standalone_mode"`
@@ -54,61 +84,163 @@ type State struct {
 
 var api = map[string]map[string]string{
 	"v1": {
-		"state":     "/v1/console/server/state",
-		"token":     "/v1/auth/login",
-		"list_ns":   "/v1/console/namespaces",
-		"ns":        "/v1/console/namespaces",
-		"cs":        "/v1/cs/configs",
-		"list_cs":   "/v1/cs/configs",
-		"user":      "/v1/auth/users",
-		"list_user": "/v1/auth/users",
-		"role":      "/v1/auth/roles",
-		"list_role": "/v1/auth/roles",
-		"perm":      "/v1/auth/permissions",
-		"list_perm": "/v1/auth/permissions",
+		"state":         "/v1/console/server/state",
+		"token":         "/v1/auth/login",
+		"list_ns":       "/v1/console/namespaces",
+		"ns":            "/v1/console/namespaces",
+		"cs":            "/v1/cs/configs",
+		"list_cs":       "/v1/cs/configs",
+		"beta_cs":       "/v1/cs/configs",
+		"clone_cs":      "/v1/cs/configs",
+		"user":          "/v1/auth/users",
+		"list_user":     "/v1/auth/users",
+		"role":          "/v1/auth/roles",
+		"list_role":     "/v1/auth/roles",
+		"perm":          "/v1/auth/permissions",
+		"list_perm":     "/v1/auth/permissions",
+		"history":       "/v1/cs/history",
+		"list_history":  "/v1/cs/history",
+		"prev_history":  "/v1/cs/history/previous",
+		"svc":           "/v1/ns/service",
+		"list_svc":      "/v1/ns/catalog/services",
+		"list_instance": "/v1/ns/catalog/instances",
+		"instance":      "/v1/ns/instance",
+		"beat":          "/v1/ns/instance/beat",
+		"list_node":     "/v1/core/cluster/nodes",
+		"listener":      "/v1/cs/configs/listener",
 	},
 	"v3": {
-		"state":     "/v3/console/server/state",
-		"token":     "/v3/auth/user/login",
-		"list_ns":   "/v3/console/core/namespace/list",
-		"ns":        "/v3/console/core/namespace",
-		"cs":        "/v3/console/cs/config",
-		"list_cs":   "/v3/console/cs/config/list",
-		"list_user": "/v3/auth/user/list",
-		"user":      "/v3/auth/user",
-		"list_role": "/v3/auth/role/list",
-		"role":      "/v3/auth/role",
-		"perm":      "/v3/auth/permission",
-		"list_perm": "/v3/auth/permission/list",
+		"state":         "/v3/console/server/state",
+		"token":         "/v3/auth/user/login",
+		"list_ns":       "/v3/console/core/namespace/list",
+		"ns":            "/v3/console/core/namespace",
+		"cs":            "/v3/console/cs/config",
+		"list_cs":       "/v3/console/cs/config/list",
+		"beta_cs":       "/v3/console/cs/config/beta",
+		"clone_cs":      "/v3/console/cs/config/clone",
+		"list_user":     "/v3/auth/user/list",
+		"user":          "/v3/auth/user",
+		"list_role":     "/v3/auth/role/list",
+		"role":          "/v3/auth/role",
+		"perm":          "/v3/auth/permission",
+		"list_perm":     "/v3/auth/permission/list",
+		"history":       "/v3/console/cs/history",
+		"list_history":  "/v3/console/cs/history/list",
+		"prev_history":  "/v3/console/cs/history/previous",
+		"svc":           "/v3/console/ns/service",
+		"list_svc":      "/v3/console/ns/service/list",
+		"list_instance": "/v3/console/ns/instance/list",
+		"list_node":     "/v3/console/core/cluster/nodes",
+		// the v3 console api can't register or deregister instances
+		"instance": "/v3/admin/ns/instance",
+		// v3 clients beat over grpc, the v1 http api is still served
+		"beat": "/v1/ns/instance/beat",
+		// there is no long-polling api in the v3 console, the v1 one is still served
+		"listener": "/v1/cs/configs/listener",
 	},
 }
 
-func NewClient(url, user, password string) *Client {
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with hc instead of http.DefaultClient,
+// e.g. to set a timeout, tls certificates or a proxy.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+func NewClient(url, user, password string, opts ...Option) *Client {
 	client := &Client{
 		URL:      url,
 		User:     user,
 		Password: password,
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
 	client.DetectAPIVersion()
 	return client
 }
 
+// WithContext sends the requests of NewClient, which detects the version of
+// the server, and of the methods without a context argument with ctx.
+func WithContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.ctx = ctx
+	}
+}
+
+// WithContext returns a shallow copy of c whose methods without a context
+// argument send their requests with ctx.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// HTTPClient returns the client that sends the requests.
+func (c *Client) HTTPClient() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+func (c *Client) postForm(ctx context.Context, url string, v url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	return c.do(req)
+}
+
 func (c *Client) DetectAPIVersion() {
+	c.DetectAPIVersionCtx(c.context())
+}
+
+func (c *Client) DetectAPIVersionCtx(ctx context.Context) {
 	for _, ver := range []string{"v3", "v1"} {
 		c.APIVersion = ver
-		v, err := c.GetVersion()
+		v, err := c.GetVersionCtx(ctx)
 		if err == nil && v != "" {
 			return
 		}
+		// the server can't be reached, asking for the other version won't help
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			break
+		}
 	}
 	c.APIVersion = "v1"
 }
 
 func (c *Client) GetVersion() (string, error) {
+	return c.GetVersionCtx(c.context())
+}
+
+func (c *Client) GetVersionCtx(ctx context.Context) (string, error) {
 	if c.State != nil {
 		return c.Version, nil
 	}
-	resp, err := http.Get(c.URL + api[c.APIVersion]["state"])
+	resp, err := c.get(ctx, c.URL+api[c.APIVersion]["state"])
 	err = decode(resp, err, &c.State)
 	if err != nil {
 		return "", err
@@ -116,32 +248,94 @@ func (c *Client) GetVersion() (string, error) {
 	return c.Version, err
 }
 
+// ListClusterNodes returns the members of the nacos cluster as seen by the
+// node c is connected to.
+func (c *Client) ListClusterNodes() (*NodeList, error) {
+	return c.ListClusterNodesCtx(c.context())
+}
+
+func (c *Client) ListClusterNodesCtx(ctx context.Context) (*NodeList, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add("withInstances", "false")
+	v.Add("pageNo", "1")
+	v.Add("pageSize", "1000")
+	v.Add("accessToken", token)
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["list_node"], v.Encode())
+	resp, err := c.get(ctx, url)
+	nodes := new(NodeList)
+	err = decodeResult(resp, err, &nodes.Items)
+	return nodes, err
+}
+
+// GetToken returns the access token, it logs in again when the token is
+// about to expire.
 func (c *Client) GetToken() (string, error) {
-	if c.Token != nil && !c.Token.Expired() {
+	return c.GetTokenCtx(c.context())
+}
+
+func (c *Client) GetTokenCtx(ctx context.Context) (string, error) {
+	if c.Token == nil && c.TokenStore != nil {
+		// a token that can't be loaded is replaced by a new one
+		c.Token, _ = c.TokenStore.Load()
+		c.storedToken = c.Token != nil
+	}
+	if c.Token != nil && !c.Token.ExpiresWithin(tokenRefreshWindow) {
 		return c.AccessToken, nil
 	}
+	if err := c.LoginCtx(ctx); err != nil {
+		return "", err
+	}
+	return c.AccessToken, nil
+}
+
+// Login gets a new token from the server and saves it in the TokenStore.
+func (c *Client) Login() error {
+	return c.LoginCtx(c.context())
+}
+
+func (c *Client) LoginCtx(ctx context.Context) error {
+	if c.Password == "" && c.PasswordFunc != nil {
+		password, err := c.PasswordFunc()
+		if err != nil {
+			return err
+		}
+		c.Password = password
+	}
 	v := url.Values{}
 	v.Add("username", c.User)
 	v.Add("password", c.Password)
 	now := time.Now().Unix()
-	resp, err := http.PostForm(c.URL+api[c.APIVersion]["token"], v)
-	err = decode(resp, err, &c.Token)
-	if err != nil {
-		return "", err
+	resp, err := c.postForm(ctx, c.URL+api[c.APIVersion]["token"], v)
+	token := new(Token)
+	if err := decode(resp, err, token); err != nil {
+		return err
+	}
+	token.ExpiredAt = now + token.TokenTTL
+	c.Token = token
+	c.storedToken = false
+	if c.TokenStore != nil {
+		return c.TokenStore.Save(token)
 	}
-	c.ExpiredAt = now + c.TokenTTL
-	return c.AccessToken, err
+	return nil
 }
 
 func (c *Client) ListNamespace() (*NamespaceList, error) {
-	token, err := c.GetToken()
+	return c.ListNamespaceCtx(c.context())
+}
+
+func (c *Client) ListNamespaceCtx(ctx context.Context) (*NamespaceList, error) {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	v := url.Values{}
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["list_ns"], v.Encode())
-	resp, err := http.Get(url)
+	resp, err := c.get(ctx, url)
 	namespaces := new(NamespaceList)
 	err = decode(resp, err, namespaces)
 	return namespaces, err
@@ -154,7 +348,11 @@ type CreateNsOpts struct {
 }
 
 func (c *Client) CreateNamespace(opts *CreateNsOpts) error {
-	token, err := c.GetToken()
+	return c.CreateNamespaceCtx(c.context(), opts)
+}
+
+func (c *Client) CreateNamespaceCtx(ctx context.Context, opts *CreateNsOpts) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -163,12 +361,16 @@ func (c *Client) CreateNamespace(opts *CreateNsOpts) error {
 	v.Add("namespaceName", opts.Name)
 	v.Add("namespaceDesc", opts.Description)
 	v.Add("accessToken", token)
-	resp, err := http.PostForm(c.URL+api[c.APIVersion]["ns"], v)
+	resp, err := c.postForm(ctx, c.URL+api[c.APIVersion]["ns"], v)
 	return checkErr(resp, err)
 }
 
 func (c *Client) DeleteNamespace(id string) error {
-	token, err := c.GetToken()
+	return c.DeleteNamespaceCtx(c.context(), id)
+}
+
+func (c *Client) DeleteNamespaceCtx(ctx context.Context, id string) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -176,16 +378,20 @@ func (c *Client) DeleteNamespace(id string) error {
 	v.Add("namespaceId", id)
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["ns"], v.Encode())
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(req)
 	return checkErr(resp, err)
 }
 
 func (c *Client) UpdateNamespace(opts *CreateNsOpts) error {
-	token, err := c.GetToken()
+	return c.UpdateNamespaceCtx(c.context(), opts)
+}
+
+func (c *Client) UpdateNamespaceCtx(ctx context.Context, opts *CreateNsOpts) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -197,31 +403,39 @@ func (c *Client) UpdateNamespace(opts *CreateNsOpts) error {
 	v.Add("namespaceDesc", opts.Description)
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["ns"], v.Encode())
-	req, err := http.NewRequest(http.MethodPut, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, nil)
 	if err != nil {
 		return err
 	}
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(req)
 	return checkErr(resp, err)
 }
 
 func (c *Client) CreateOrUpdateNamespace(opts *CreateNsOpts) error {
-	nsList, err := c.ListNamespace()
+	return c.CreateOrUpdateNamespaceCtx(c.context(), opts)
+}
+
+func (c *Client) CreateOrUpdateNamespaceCtx(ctx context.Context, opts *CreateNsOpts) error {
+	nsList, err := c.ListNamespaceCtx(ctx)
 	if err != nil {
 		return err
 	}
 	for _, ns := range nsList.Items {
 		if ns.ID == opts.ID {
-			return c.UpdateNamespace(opts)
+			return c.UpdateNamespaceCtx(ctx, opts)
 		}
 	}
-	return c.CreateNamespace(opts)
+	return c.CreateNamespaceCtx(ctx, opts)
 }
 
 func (c *Client) GetNamespace(id string) (*Namespace, error) {
-	nsList, err := c.ListNamespace()
+	return c.GetNamespaceCtx(c.context(), id)
+}
+
+func (c *Client) GetNamespaceCtx(ctx context.Context, id string) (*Namespace, error) {
+	nsList, err := c.ListNamespaceCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +444,7 @@ func (c *Client) GetNamespace(id string) (*Namespace, error) {
 			return ns, nil
 		}
 	}
-	return nil, fmt.Errorf("404 Not Found %s", id)
+	return nil, notFound("namespace "+id, nil)
 }
 
 type GetCfgOpts struct {
@@ -240,7 +454,11 @@ type GetCfgOpts struct {
 }
 
 func (c *Client) GetConfig(opts *GetCfgOpts) (*Configuration, error) {
-	token, err := c.GetToken()
+	return c.GetConfigCtx(c.context(), opts)
+}
+
+func (c *Client) GetConfigCtx(ctx context.Context, opts *GetCfgOpts) (*Configuration, error) {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +471,7 @@ func (c *Client) GetConfig(opts *GetCfgOpts) (*Configuration, error) {
 	v.Add("show", "all")
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["cs"], v.Encode())
-	resp, err := http.Get(url)
+	resp, err := c.get(ctx, url)
 	cfg := new(ConfigurationV3)
 	if c.APIVersion == "v3" {
 		err = decode(resp, err, cfg)
@@ -261,9 +479,10 @@ func (c *Client) GetConfig(opts *GetCfgOpts) (*Configuration, error) {
 		cfg.Data = new(Configuration)
 		err = decode(resp, err, cfg.Data)
 	}
-	// if config not found, nacos server return 200 and empty response
-	if err == io.EOF {
-		return nil, fmt.Errorf("404 Not Found %s %w", url, err)
+	// if config not found, nacos server return 200 and empty response, or
+	// null data on v3
+	if err == io.EOF || (err == nil && cfg.Data == nil) {
+		return nil, notFound(fmt.Sprintf("configuration %s/%s/%s", opts.NamespaceID, opts.Group, opts.DataID), err)
 	}
 	return cfg.Data, err
 }
@@ -280,7 +499,11 @@ type ListCfgOpts struct {
 }
 
 func (c *Client) ListConfig(opts *ListCfgOpts) (*ConfigurationList, error) {
-	token, err := c.GetToken()
+	return c.ListConfigCtx(c.context(), opts)
+}
+
+func (c *Client) ListConfigCtx(ctx context.Context, opts *ListCfgOpts) (*ConfigurationList, error) {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -304,7 +527,7 @@ func (c *Client) ListConfig(opts *ListCfgOpts) (*ConfigurationList, error) {
 	v.Add("search", "accurate")
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["list_cs"], v.Encode())
-	resp, err := http.Get(url)
+	resp, err := c.get(ctx, url)
 	cfgList := new(ConfigurationListV3)
 	if c.APIVersion == "v3" {
 		err = decode(resp, err, cfgList)
@@ -315,12 +538,16 @@ func (c *Client) ListConfig(opts *ListCfgOpts) (*ConfigurationList, error) {
 }
 
 func (c *Client) ListConfigInNs(namespace, group string) (*ConfigurationList, error) {
+	return c.ListConfigInNsCtx(c.context(), namespace, group)
+}
+
+func (c *Client) ListConfigInNsCtx(ctx context.Context, namespace, group string) (*ConfigurationList, error) {
 	nsCs := new(ConfigurationList)
 	listOpts := ListCfgOpts{PageNumber: 1, PageSize: 100, Group: group, NamespaceID: namespace}
 	for {
-		cs, err := c.ListConfig(&listOpts)
+		cs, err := c.ListConfigCtx(ctx, &listOpts)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		nsCs.Items = append(nsCs.Items, cs.Items...)
 		if cs.PagesAvailable == 0 || cs.PagesAvailable == cs.PageNumber {
@@ -332,13 +559,17 @@ func (c *Client) ListConfigInNs(namespace, group string) (*ConfigurationList, er
 }
 
 func (c *Client) ListAllConfig() (*ConfigurationList, error) {
+	return c.ListAllConfigCtx(c.context())
+}
+
+func (c *Client) ListAllConfigCtx(ctx context.Context) (*ConfigurationList, error) {
 	allCs := new(ConfigurationList)
-	nss, err := c.ListNamespace()
+	nss, err := c.ListNamespaceCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	for _, ns := range nss.Items {
-		cs, err := c.ListConfigInNs(ns.ID, "")
+		cs, err := c.ListConfigInNsCtx(ctx, ns.ID, "")
 		if err != nil {
 			return nil, err
 		}
@@ -347,6 +578,70 @@ func (c *Client) ListAllConfig() (*ConfigurationList, error) {
 	return allCs, nil
 }
 
+func (c *Client) ListConfigHistory(opts *GetCfgOpts) (*ConfigHistoryList, error) {
+	return c.ListConfigHistoryCtx(c.context(), opts)
+}
+
+func (c *Client) ListConfigHistoryCtx(ctx context.Context, opts *GetCfgOpts) (*ConfigHistoryList, error) {
+	v := url.Values{}
+	v.Add("dataId", opts.DataID)
+	v.Add("group", opts.Group)
+	v.Add("groupName", opts.Group)
+	v.Add("tenant", opts.NamespaceID)
+	v.Add("namespaceId", opts.NamespaceID)
+	if c.APIVersion == "v1" {
+		return listResource[ConfigHistoryList](ctx, c, api[c.APIVersion]["list_history"], v)
+	}
+	return listResource[ConfigHistoryListV3](ctx, c, api[c.APIVersion]["list_history"], v)
+}
+
+type GetHistoryOpts struct {
+	ID          string
+	DataID      string
+	Group       string
+	NamespaceID string
+}
+
+func (c *Client) GetConfigHistory(opts *GetHistoryOpts) (*ConfigHistory, error) {
+	return c.GetConfigHistoryCtx(c.context(), opts)
+}
+
+func (c *Client) GetConfigHistoryCtx(ctx context.Context, opts *GetHistoryOpts) (*ConfigHistory, error) {
+	return c.getConfigHistory(ctx, api[c.APIVersion]["history"], "nid", opts)
+}
+
+// GetPreviousConfig returns the history entry that precedes the one with opts.ID.
+func (c *Client) GetPreviousConfig(opts *GetHistoryOpts) (*ConfigHistory, error) {
+	return c.GetPreviousConfigCtx(c.context(), opts)
+}
+
+func (c *Client) GetPreviousConfigCtx(ctx context.Context, opts *GetHistoryOpts) (*ConfigHistory, error) {
+	return c.getConfigHistory(ctx, api[c.APIVersion]["prev_history"], "id", opts)
+}
+
+func (c *Client) getConfigHistory(ctx context.Context, endpoint, idKey string, opts *GetHistoryOpts) (*ConfigHistory, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add(idKey, opts.ID)
+	v.Add("dataId", opts.DataID)
+	v.Add("group", opts.Group)
+	v.Add("groupName", opts.Group)
+	v.Add("tenant", opts.NamespaceID)
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("accessToken", token)
+	url := fmt.Sprintf("%s%s?%s", c.URL, endpoint, v.Encode())
+	resp, err := c.get(ctx, url)
+	history := new(ConfigHistory)
+	err = c.decodeData(resp, err, history)
+	if err == io.EOF {
+		return nil, notFound(fmt.Sprintf("history %s of configuration %s/%s/%s", opts.ID, opts.NamespaceID, opts.Group, opts.DataID), err)
+	}
+	return history, err
+}
+
 type CreateCfgOpts struct {
 	Application string
 	Content     string
@@ -356,10 +651,20 @@ type CreateCfgOpts struct {
 	NamespaceID string
 	Tags        string
 	Type        string
+	// BetaIps is a comma separated list of client ips, when set the content
+	// is published only to them as a gray release.
+	BetaIps string
+	// CasMd5 is the md5 the configuration is expected to have on the server,
+	// when set the publish fails with a *ConflictError if it does not match.
+	CasMd5 string
 }
 
 func (c *Client) CreateConfig(opts *CreateCfgOpts) error {
-	token, err := c.GetToken()
+	return c.CreateConfigCtx(c.context(), opts)
+}
+
+func (c *Client) CreateConfigCtx(ctx context.Context, opts *CreateCfgOpts) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -376,14 +681,180 @@ func (c *Client) CreateConfig(opts *CreateCfgOpts) error {
 	v.Add("config_tags", opts.Tags)
 	v.Add("configTags", opts.Tags)
 	v.Add("accessToken", token)
-	resp, err := http.PostForm(c.URL+api[c.APIVersion]["cs"], v)
+	if opts.BetaIps != "" {
+		v.Add("betaIps", opts.BetaIps)
+	}
+	if opts.CasMd5 != "" {
+		v.Add("casMd5", opts.CasMd5)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+api[c.APIVersion]["cs"], strings.NewReader(v.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	if opts.BetaIps != "" {
+		req.Header.Add("betaIps", opts.BetaIps)
+	}
+	// the v1 api reads casMd5 from the header, the v3 one from the form
+	if opts.CasMd5 != "" {
+		req.Header.Add("casMd5", opts.CasMd5)
+	}
+	resp, err := c.do(req)
+	err = checkErr(resp, err)
+	if opts.CasMd5 != "" && IsConflict(err) {
+		return &ConflictError{DataID: opts.DataID, Group: opts.Group, NamespaceID: opts.NamespaceID, Md5: opts.CasMd5, Err: err}
+	}
+	return err
+}
+
+func (c *Client) GetBetaConfig(opts *GetCfgOpts) (*BetaConfiguration, error) {
+	return c.GetBetaConfigCtx(c.context(), opts)
+}
+
+func (c *Client) GetBetaConfigCtx(ctx context.Context, opts *GetCfgOpts) (*BetaConfiguration, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add("beta", "true")
+	v.Add("dataId", opts.DataID)
+	v.Add("group", opts.Group)
+	v.Add("groupName", opts.Group)
+	v.Add("tenant", opts.NamespaceID)
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("accessToken", token)
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["beta_cs"], v.Encode())
+	resp, err := c.get(ctx, url)
+	// both api versions wrap the beta configuration in {code, message, data}
+	var beta *BetaConfiguration
+	if err := decodeResult(resp, err, &beta); err != nil {
+		return nil, err
+	}
+	if beta == nil {
+		return nil, notFound(fmt.Sprintf("beta configuration %s/%s/%s", opts.NamespaceID, opts.Group, opts.DataID), nil)
+	}
+	return beta, nil
+}
+
+func (c *Client) StopBetaConfig(opts *GetCfgOpts) error {
+	return c.StopBetaConfigCtx(c.context(), opts)
+}
+
+func (c *Client) StopBetaConfigCtx(ctx context.Context, opts *GetCfgOpts) error {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return err
+	}
+	v := url.Values{}
+	v.Add("beta", "true")
+	v.Add("dataId", opts.DataID)
+	v.Add("group", opts.Group)
+	v.Add("groupName", opts.Group)
+	v.Add("tenant", opts.NamespaceID)
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("accessToken", token)
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["beta_cs"], v.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
 	return checkErr(resp, err)
 }
 
+// PromoteBetaConfig publishes the beta content to all clients and stops the
+// beta release.
+func (c *Client) PromoteBetaConfig(opts *GetCfgOpts) error {
+	return c.PromoteBetaConfigCtx(c.context(), opts)
+}
+
+func (c *Client) PromoteBetaConfigCtx(ctx context.Context, opts *GetCfgOpts) error {
+	beta, err := c.GetBetaConfigCtx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	createOpts := &CreateCfgOpts{
+		DataID:      opts.DataID,
+		Group:       opts.Group,
+		NamespaceID: opts.NamespaceID,
+		Content:     beta.Content,
+		Type:        beta.Type,
+		Application: beta.Application,
+	}
+	cfg, err := c.GetConfigCtx(ctx, opts)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	if cfg != nil {
+		createOpts.Type = cfg.Type
+		createOpts.Application = cfg.Application
+		createOpts.Description = cfg.Description
+		createOpts.Tags = cfg.Tags
+	}
+	if err := c.CreateConfigCtx(ctx, createOpts); err != nil {
+		return err
+	}
+	return c.StopBetaConfigCtx(ctx, opts)
+}
+
+type CloneCfgOpts struct {
+	// NamespaceID is the namespace the configurations are cloned to
+	NamespaceID string
+	Policy      ConflictPolicy
+	// Items are the configurations to clone, as returned by ListConfig
+	Items []*Configuration
+}
+
+// CloneConfig asks the server to copy configurations into another namespace.
+func (c *Client) CloneConfig(opts *CloneCfgOpts) (*CloneResult, error) {
+	return c.CloneConfigCtx(c.context(), opts)
+}
+
+func (c *Client) CloneConfigCtx(ctx context.Context, opts *CloneCfgOpts) (*CloneResult, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add("clone", "true")
+	v.Add("tenant", opts.NamespaceID)
+	v.Add("targetNamespaceId", opts.NamespaceID)
+	v.Add("policy", string(opts.Policy))
+	v.Add("accessToken", token)
+	type cloneItem struct {
+		ID     json.Number `json:"cfgId"`
+		DataID string      `json:"dataId"`
+		Group  string      `json:"group"`
+	}
+	items := []cloneItem{}
+	for _, cfg := range opts.Items {
+		items = append(items, cloneItem{ID: json.Number(cfg.ID), DataID: cfg.DataID, Group: cfg.GetGroup()})
+	}
+	body, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["clone_cs"], v.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := c.do(req)
+	result := new(CloneResult)
+	err = decodeResult(resp, err, result)
+	return result, err
+}
+
 type DeleteCfgOpts = GetCfgOpts
 
 func (c *Client) DeleteConfig(opts *DeleteCfgOpts) error {
-	token, err := c.GetToken()
+	return c.DeleteConfigCtx(c.context(), opts)
+}
+
+func (c *Client) DeleteConfigCtx(ctx context.Context, opts *DeleteCfgOpts) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -395,17 +866,21 @@ func (c *Client) DeleteConfig(opts *DeleteCfgOpts) error {
 	v.Add("namespaceId", opts.NamespaceID)
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["cs"], v.Encode())
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(req)
 	return checkErr(resp, err)
 }
 
 func (c *Client) CreateUser(name, password string) error {
-	token, err := c.GetToken()
+	return c.CreateUserCtx(c.context(), name, password)
+}
+
+func (c *Client) CreateUserCtx(ctx context.Context, name, password string) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -413,12 +888,16 @@ func (c *Client) CreateUser(name, password string) error {
 	v.Add("username", name)
 	v.Add("password", password)
 	v.Add("accessToken", token)
-	resp, err := http.PostForm(c.URL+api[c.APIVersion]["user"], v)
+	resp, err := c.postForm(ctx, c.URL+api[c.APIVersion]["user"], v)
 	return checkErr(resp, err)
 }
 
 func (c *Client) DeleteUser(name string) error {
-	token, err := c.GetToken()
+	return c.DeleteUserCtx(c.context(), name)
+}
+
+func (c *Client) DeleteUserCtx(ctx context.Context, name string) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -426,24 +905,56 @@ func (c *Client) DeleteUser(name string) error {
 	v.Add("username", name)
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["user"], v.Encode())
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(req)
+	return checkErr(resp, err)
+}
+
+// UpdateUser changes the password of user name.
+func (c *Client) UpdateUser(name, password string) error {
+	return c.UpdateUserCtx(c.context(), name, password)
+}
+
+func (c *Client) UpdateUserCtx(ctx context.Context, name, password string) error {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return err
+	}
+	v := url.Values{}
+	v.Add("username", name)
+	v.Add("newPassword", password)
+	v.Add("accessToken", token)
+	// the password goes in the body, not the url which ends up in access logs
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.URL+api[c.APIVersion]["user"], strings.NewReader(v.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.do(req)
 	return checkErr(resp, err)
 }
 
 func (c *Client) ListUser() (*UserList, error) {
+	return c.ListUserCtx(c.context())
+}
+
+func (c *Client) ListUserCtx(ctx context.Context) (*UserList, error) {
 	if c.APIVersion == "v1" {
-		return listResource[UserList](c, api[c.APIVersion]["list_user"])
+		return listResource[UserList](ctx, c, api[c.APIVersion]["list_user"], nil)
 	}
-	return listResource[UserListV3](c, api[c.APIVersion]["list_user"])
+	return listResource[UserListV3](ctx, c, api[c.APIVersion]["list_user"], nil)
 }
 
 func (c *Client) GetUser(name string) (*User, error) {
-	users, err := c.ListUser()
+	return c.GetUserCtx(c.context(), name)
+}
+
+func (c *Client) GetUserCtx(ctx context.Context, name string) (*User, error) {
+	users, err := c.ListUserCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -453,11 +964,15 @@ func (c *Client) GetUser(name string) (*User, error) {
 			return user, nil
 		}
 	}
-	return nil, fmt.Errorf("404 Not Found %s", name)
+	return nil, notFound("user "+name, nil)
 }
 
 func (c *Client) CreateRole(name, username string) error {
-	token, err := c.GetToken()
+	return c.CreateRoleCtx(c.context(), name, username)
+}
+
+func (c *Client) CreateRoleCtx(ctx context.Context, name, username string) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -465,12 +980,16 @@ func (c *Client) CreateRole(name, username string) error {
 	v.Add("username", username)
 	v.Add("role", name)
 	v.Add("accessToken", token)
-	resp, err := http.PostForm(c.URL+api[c.APIVersion]["role"], v)
+	resp, err := c.postForm(ctx, c.URL+api[c.APIVersion]["role"], v)
 	return checkErr(resp, err)
 }
 
 func (c *Client) DeleteRole(name, username string) error {
-	token, err := c.GetToken()
+	return c.DeleteRoleCtx(c.context(), name, username)
+}
+
+func (c *Client) DeleteRoleCtx(ctx context.Context, name, username string) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -479,23 +998,31 @@ func (c *Client) DeleteRole(name, username string) error {
 	v.Add("role", name)
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["role"], v.Encode())
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(req)
 	return checkErr(resp, err)
 }
 
 func (c *Client) ListRole() (*RoleList, error) {
+	return c.ListRoleCtx(c.context())
+}
+
+func (c *Client) ListRoleCtx(ctx context.Context) (*RoleList, error) {
 	if c.APIVersion == "v1" {
-		return listResource[RoleList](c, api[c.APIVersion]["list_role"])
+		return listResource[RoleList](ctx, c, api[c.APIVersion]["list_role"], nil)
 	}
-	return listResource[RoleListV3](c, api[c.APIVersion]["list_role"])
+	return listResource[RoleListV3](ctx, c, api[c.APIVersion]["list_role"], nil)
 }
 
 func (c *Client) GetRole(name, username string) (*Role, error) {
-	roles, err := c.ListRole()
+	return c.GetRoleCtx(c.context(), name, username)
+}
+
+func (c *Client) GetRoleCtx(ctx context.Context, name, username string) (*Role, error) {
+	roles, err := c.ListRoleCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -503,11 +1030,15 @@ func (c *Client) GetRole(name, username string) (*Role, error) {
 	if roles.Contains(r) {
 		return &r, nil
 	}
-	return nil, fmt.Errorf("404 Not Found %s:%s", name, username)
+	return nil, notFound(fmt.Sprintf("role %s:%s", name, username), nil)
 }
 
 func (c *Client) CreatePermission(role, resource, permission string) error {
-	token, err := c.GetToken()
+	return c.CreatePermissionCtx(c.context(), role, resource, permission)
+}
+
+func (c *Client) CreatePermissionCtx(ctx context.Context, role, resource, permission string) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -516,12 +1047,16 @@ func (c *Client) CreatePermission(role, resource, permission string) error {
 	v.Add("resource", resource)
 	v.Add("role", role)
 	v.Add("accessToken", token)
-	resp, err := http.PostForm(c.URL+api[c.APIVersion]["perm"], v)
+	resp, err := c.postForm(ctx, c.URL+api[c.APIVersion]["perm"], v)
 	return checkErr(resp, err)
 }
 
 func (c *Client) DeletePermission(role, resource, permission string) error {
-	token, err := c.GetToken()
+	return c.DeletePermissionCtx(c.context(), role, resource, permission)
+}
+
+func (c *Client) DeletePermissionCtx(ctx context.Context, role, resource, permission string) error {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return err
 	}
@@ -531,23 +1066,31 @@ func (c *Client) DeletePermission(role, resource, permission string) error {
 	v.Add("role", role)
 	v.Add("accessToken", token)
 	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["perm"], v.Encode())
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(req)
 	return checkErr(resp, err)
 }
 
 func (c *Client) ListPermission() (*PermissionList, error) {
+	return c.ListPermissionCtx(c.context())
+}
+
+func (c *Client) ListPermissionCtx(ctx context.Context) (*PermissionList, error) {
 	if c.APIVersion == "v1" {
-		return listResource[PermissionList](c, api[c.APIVersion]["list_perm"])
+		return listResource[PermissionList](ctx, c, api[c.APIVersion]["list_perm"], nil)
 	}
-	return listResource[PermissionListV3](c, api[c.APIVersion]["list_perm"])
+	return listResource[PermissionListV3](ctx, c, api[c.APIVersion]["list_perm"], nil)
 }
 
 func (c *Client) GetPermission(role, resource, action string) (*Permission, error) {
-	perms, err := c.ListPermission()
+	return c.GetPermissionCtx(c.context(), role, resource, action)
+}
+
+func (c *Client) GetPermissionCtx(ctx context.Context, role, resource, action string) (*Permission, error) {
+	perms, err := c.ListPermissionCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -555,20 +1098,13 @@ func (c *Client) GetPermission(role, resource, action string) (*Permission, erro
 	if perms.Contains(p) {
 		return &p, nil
 	}
-	return nil, fmt.Errorf("404 Not Found %s:%s:%s", role, resource, action)
+	return nil, notFound(fmt.Sprintf("permission %s:%s:%s", role, resource, action), nil)
 }
 
 func checkStatus(resp *http.Response) error {
 	if resp.StatusCode != http.StatusOK {
 		data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-		if err != nil {
-			return fmt.Errorf("%s %s %w", resp.Status, resp.Request.URL, err)
-		}
-		// no data or html data
-		if len(data) == 0 || data[0] == '<' {
-			return fmt.Errorf("%s %s", resp.Status, resp.Request.URL)
-		}
-		return fmt.Errorf("%s %s %s", resp.Status, resp.Request.URL, data)
+		return newNacosErr(resp, data, err)
 	}
 	return nil
 }
@@ -592,14 +1128,18 @@ func decode(resp *http.Response, httpErr error, v any) error {
 	return json.NewDecoder(resp.Body).Decode(v)
 }
 
-// type NacosErr struct {
-// 	StatusCode int
-// 	Err        error
-// 	URL        string
-// }
-
-// func (e *NacosErr) Error() string {
-// 	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.URL, e.Err.Error())
-// }
+// decodeData decodes the response into v, unwrapping the {code, message, data}
+// envelope used by the v3 api.
+func (c *Client) decodeData(resp *http.Response, httpErr error, v any) error {
+	if c.APIVersion == "v1" {
+		return decode(resp, httpErr, v)
+	}
+	return decodeResult(resp, httpErr, v)
+}
 
-// func (e *NacosErr) Unwrap() error { return e.Err }
+// decodeResult decodes the data field of a {code, message, data} response into v.
+func decodeResult(resp *http.Response, httpErr error, v any) error {
+	return decode(resp, httpErr, &struct {
+		Data any `json:"data"`
+	}{Data: v})
+}
@@ -1,11 +1,16 @@
 package nacos
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -83,6 +88,18 @@ var roleListV3 = newV3Data(roleList)
 var permList = newV1Data(permission)
 var permListV3 = newV3Data(permList)
 
+var beta = newV3Data(`{"dataId": "test", "group": "DEFAULT_GROUP", "tenant": "test-tenant", "content": "beta content", "betaIps": "127.0.0.1,127.0.0.2"}`)
+
+var history = `{"id": "2", "lastId": 1, "dataId": "test", "group": "DEFAULT_GROUP", "tenant": "test-tenant", "content": "test content", "opType": "U         ", "createdTime": "2010-05-04T16:00:00.000+0000", "lastModifiedTime": 1700000000000}`
+var historyV3 = newV3Data(history)
+var historyList = newV1Data(history)
+var historyListV3 = newV3Data(historyList)
+
+var node = `{"ip": "10.0.0.%d", "port": 8848, "state": "%s", "address": "10.0.0.%[1]d:8848", "failAccessCnt": 0,
+	"extendInfo": {"lastRefreshTime": 1700000000000, "raftPort": "7848", "version": "2.4.0",
+		"raftMetaData": {"metaDataMap": {"naming_persistent_service_v2": {"leader": "10.0.0.1:7848", "raftGroupMember": ["10.0.0.1:7848", "10.0.0.2:7848"], "term": 3}}}}}`
+var nodeList = newV3Data("[" + fmt.Sprintf(node, 1, "UP") + "," + fmt.Sprintf(node, 2, "DOWN") + "]")
+
 func TestNewClient(t *testing.T) {
 	c := NewClient("http://localhost:8848", "user", "password")
 	assert.Equal(t, "http://localhost:8848", c.URL)
@@ -90,6 +107,46 @@ func TestNewClient(t *testing.T) {
 	assert.Equal(t, "password", c.Password)
 }
 
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientWithHTTPClient(t *testing.T) {
+	ts, _ := startServer()
+	defer ts.Close()
+	transport := &countingTransport{}
+	c := NewClient(ts.URL, "user", "password", WithHTTPClient(&http.Client{Transport: transport}))
+	_, err := c.ListNamespace()
+	assert.NoError(t, err)
+	// version detection, login and list
+	assert.Equal(t, 3, transport.requests)
+}
+
+func TestNewClientWithContext(t *testing.T) {
+	blocked := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-blocked
+	}))
+	defer ts.Close()
+	defer close(blocked)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// the version detection of a hanging server stops with ctx
+	done := make(chan *Client)
+	go func() { done <- NewClient(ts.URL, "user", "password", WithContext(ctx)) }()
+	select {
+	case c := <-done:
+		assert.Equal(t, ctx, c.context())
+	case <-time.After(5 * time.Second):
+		t.Fatal("NewClient did not stop with its context")
+	}
+}
+
 func startServer() (*httptest.Server, *Client) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -101,13 +158,17 @@ func startServer() (*httptest.Server, *Client) {
 				w.Write([]byte(nsList))
 			}
 		case "/v1/cs/configs":
-			if r.URL.Query().Get("show") == "all" {
+			if r.URL.Query().Get("beta") == "true" {
+				w.Write([]byte(beta))
+			} else if r.URL.Query().Get("show") == "all" {
 				w.Write([]byte(config))
 			} else {
 				w.Write([]byte(csList))
 			}
 		case "/v3/console/cs/config":
 			w.Write([]byte(configV3))
+		case "/v3/console/cs/config/beta":
+			w.Write([]byte(beta))
 		case "/v3/console/cs/config/list":
 			w.Write([]byte(csListV3))
 		case "/v1/console/server/state":
@@ -128,12 +189,60 @@ func startServer() (*httptest.Server, *Client) {
 			w.Write([]byte(permList))
 		case "/v3/auth/permission/list":
 			w.Write([]byte(permListV3))
+		case "/v1/cs/history":
+			if r.URL.Query().Get("nid") != "" {
+				w.Write([]byte(history))
+			} else {
+				w.Write([]byte(historyList))
+			}
+		case "/v1/cs/history/previous":
+			w.Write([]byte(history))
+		case "/v3/console/cs/history", "/v3/console/cs/history/previous":
+			w.Write([]byte(historyV3))
+		case "/v3/console/cs/history/list":
+			w.Write([]byte(historyListV3))
+		case "/v1/ns/service", "/v3/console/ns/service":
+			if r.Method != http.MethodGet {
+				w.Write([]byte("ok"))
+			} else if r.URL.Path == "/v1/ns/service" {
+				w.Write([]byte(service))
+			} else {
+				w.Write([]byte(serviceV3))
+			}
+		case "/v1/ns/catalog/services":
+			w.Write([]byte(svcList))
+		case "/v3/console/ns/service/list":
+			w.Write([]byte(svcListV3))
+		case "/v1/ns/catalog/instances":
+			w.Write([]byte(instList))
+		case "/v3/console/ns/instance/list":
+			w.Write([]byte(instListV3))
+		case "/v1/core/cluster/nodes", "/v3/console/core/cluster/nodes":
+			w.Write([]byte(nodeList))
+		case "/v1/ns/instance", "/v3/admin/ns/instance":
+			w.Write([]byte("ok"))
 		}
 	}))
 	c := NewClient(ts.URL, "user", "password")
 	return ts, c
 }
 
+// startHandlerServer starts a server which answers the login and hands the
+// other requests to h, the returned client uses v1.
+func startHandlerServer(h http.HandlerFunc) (*httptest.Server, *Client) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/login", "/v3/auth/user/login":
+			w.Write([]byte(`{"accessToken": "test-token", "tokenTtl": 3600, "globalAdmin": true}`))
+		default:
+			h(w, r)
+		}
+	}))
+	c := NewClient(ts.URL, "user", "password")
+	c.APIVersion = "v1"
+	return ts, c
+}
+
 var apiTests = []struct {
 	apiVersion  string
 	expectValue string
@@ -221,6 +330,20 @@ func TestListNamespace(t *testing.T) {
 	}
 }
 
+func TestListNamespaceCtx(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.ListNamespaceCtx(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = c.WithContext(ctx).ListNamespace()
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = c.ListNamespace()
+	assert.NoError(t, err)
+}
+
 func TestCreateNamespace(t *testing.T) {
 	ts, c := startServer()
 	defer ts.Close()
@@ -304,6 +427,24 @@ func TestGetConfig(t *testing.T) {
 	}
 }
 
+func TestGetConfigNotFound(t *testing.T) {
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v3/console/cs/config" {
+			w.Write([]byte(`{"code": 0, "message": "success", "data": null}`))
+		}
+	})
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			cfg, err := c.GetConfig(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"})
+			assert.Nil(t, cfg)
+			assert.True(t, IsNotFound(err))
+			assert.ErrorContains(t, err, "404 Not Found configuration test-tenant/DEFAULT_GROUP/test")
+		})
+	}
+}
+
 func TestListConfig(t *testing.T) {
 	ts, c := startServer()
 	defer ts.Close()
@@ -335,6 +476,16 @@ func TestListConfigInNs(t *testing.T) {
 	}
 }
 
+func TestListConfigInNsError(t *testing.T) {
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"code": 403, "message": "authorization failed!"}`))
+	})
+	defer ts.Close()
+	_, err := c.ListConfigInNs("test", "DEFAULT_GROUP")
+	assert.True(t, IsUnauthorized(err))
+}
+
 func TestListAllConfig(t *testing.T) {
 	ts, c := startServer()
 	defer ts.Close()
@@ -358,6 +509,110 @@ func TestCreateConfig(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateBetaConfig(t *testing.T) {
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/cs/configs" {
+			assert.Equal(t, "127.0.0.1", r.Header.Get("betaIps"))
+			assert.Equal(t, "beta content", r.FormValue("content"))
+		}
+	})
+	defer ts.Close()
+	err := c.CreateConfig(&CreateCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", Content: "beta content", BetaIps: "127.0.0.1"})
+	assert.NoError(t, err)
+}
+
+func TestCreateConfigCasConflict(t *testing.T) {
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/cs/configs" {
+			assert.Equal(t, "old-md5", r.Header.Get("casMd5"))
+			assert.Equal(t, "old-md5", r.FormValue("casMd5"))
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code": 20002, "message": "Cas publish fail, server md5 may have changed."}`))
+		}
+	})
+	defer ts.Close()
+	err := c.CreateConfig(&CreateCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", Content: "new", CasMd5: "old-md5"})
+	var conflict *ConflictError
+	if assert.ErrorAs(t, err, &conflict) {
+		assert.Equal(t, "old-md5", conflict.Md5)
+		assert.Equal(t, "test", conflict.DataID)
+	}
+}
+
+func TestGetBetaConfig(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			b, err := c.GetBetaConfig(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "beta content", b.Content)
+				assert.Equal(t, "127.0.0.1,127.0.0.2", b.BetaIps)
+			}
+		})
+	}
+}
+
+func TestGetBetaConfigNotFound(t *testing.T) {
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/cs/configs" {
+			w.Write([]byte(`{"code": 200, "message": "stop beta ok", "data": null}`))
+		}
+	})
+	defer ts.Close()
+	_, err := c.GetBetaConfig(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP"})
+	assert.ErrorContains(t, err, "404 Not Found")
+}
+
+func TestStopBetaConfig(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			err := c.StopBetaConfig(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPromoteBetaConfig(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			err := c.PromoteBetaConfig(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCloneConfig(t *testing.T) {
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/cs/configs", "/v3/console/cs/config/clone":
+			assert.Equal(t, "target", r.URL.Query().Get("tenant"))
+			assert.Equal(t, "SKIP", r.URL.Query().Get("policy"))
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `[{"cfgId": 1, "dataId": "test", "group": "DEFAULT_GROUP"}]`, string(body))
+			w.Write([]byte(`{"code": 200, "data": {"succCount": 0, "skipCount": 1, "skipData": [{"dataId": "test", "group": "DEFAULT_GROUP"}]}}`))
+		}
+	})
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			result, err := c.CloneConfig(&CloneCfgOpts{NamespaceID: "target", Policy: PolicySkip, Items: []*Configuration{{ID: "1", DataID: "test", Group: "DEFAULT_GROUP"}}})
+			if assert.NoError(t, err) {
+				assert.Equal(t, 1, result.SkipCount)
+				assert.Equal(t, "test", result.SkipData[0].DataID)
+			}
+		})
+	}
+}
+
 func TestDeleteConfig(t *testing.T) {
 	ts, c := startServer()
 	defer ts.Close()
@@ -397,6 +652,22 @@ func TestDeleteUser(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpdateUser(t *testing.T) {
+	var form url.Values
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/users" {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.NoError(t, r.ParseForm())
+			form = r.PostForm
+		}
+	})
+	defer ts.Close()
+	if assert.NoError(t, c.UpdateUser("user1", "new-password")) {
+		assert.Equal(t, "user1", form.Get("username"))
+		assert.Equal(t, "new-password", form.Get("newPassword"))
+	}
+}
+
 func TestGetUser(t *testing.T) {
 	ts, c := startServer()
 	defer ts.Close()
@@ -491,3 +762,120 @@ func TestGetPermission(t *testing.T) {
 		assert.Equal(t, "rw", perm.Action)
 	}
 }
+
+func TestListConfigHistory(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			hs, err := c.ListConfigHistory(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, 1, len(hs.Items))
+				assert.Equal(t, "2", hs.Items[0].ID.String())
+				assert.Equal(t, "1", hs.Items[0].LastID.String())
+			}
+		})
+	}
+}
+
+func TestGetConfigHistory(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			h, err := c.GetConfigHistory(&GetHistoryOpts{ID: "2", DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "test content", h.Content)
+				assert.Equal(t, "DEFAULT_GROUP", h.GetGroup())
+				assert.Equal(t, "test-tenant", h.GetNamespace())
+			}
+		})
+	}
+}
+
+func TestGetPreviousConfig(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			h, err := c.GetPreviousConfig(&GetHistoryOpts{ID: "3", DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "2", h.ID.String())
+			}
+		})
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`"2010-05-04T16:00:00.000+0000"`, "2010-05-04T16:00:00.000+0000"},
+		{`1700000000000`, time.UnixMilli(1700000000000).Format(time.RFC3339)},
+		{`null`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var ts Timestamp
+			if assert.NoError(t, json.Unmarshal([]byte(tt.data), &ts)) {
+				assert.Equal(t, tt.want, ts.String())
+			}
+		})
+	}
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ConflictPolicy
+		wantErr bool
+	}{
+		{"abort", PolicyAbort, false},
+		{"SKIP", PolicySkip, false},
+		{"Overwrite", PolicyOverwrite, false},
+		{"replace", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := ParseConflictPolicy(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, p)
+			}
+		})
+	}
+}
+
+func TestListClusterNodes(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			nodes, err := c.ListClusterNodes()
+			if assert.NoError(t, err) && assert.Len(t, nodes.Items, 2) {
+				assert.Equal(t, "10.0.0.1:8848", nodes.Items[0].Address)
+				assert.Equal(t, "LEADER", nodes.Items[0].RaftRole())
+				assert.Equal(t, "DOWN", nodes.Items[1].State)
+				assert.Equal(t, "FOLLOWER", nodes.Items[1].RaftRole())
+				assert.Equal(t, "2.4.0", nodes.Items[1].ExtendInfo.Version)
+			}
+		})
+	}
+}
+
+func TestRaftRole(t *testing.T) {
+	n := &Node{IP: "10.0.0.1"}
+	assert.Equal(t, "", n.RaftRole())
+	n.ExtendInfo.RaftPort = "7848"
+	n.ExtendInfo.RaftMetaData.MetaDataMap = map[string]RaftGroup{
+		"a": {Leader: "10.0.0.1:7848"},
+		"b": {Leader: "10.0.0.2:7848"},
+	}
+	assert.Equal(t, "LEADER 1/2", n.RaftRole())
+}
@@ -0,0 +1,372 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type GetSvcOpts struct {
+	NamespaceID string
+	GroupName   string
+	ServiceName string
+}
+
+// ListSvcOpts filters the services, an empty ServiceName matches all of them.
+type ListSvcOpts = GetSvcOpts
+
+func (c *Client) ListService(opts *ListSvcOpts) (*ServiceList, error) {
+	return c.ListServiceCtx(c.context(), opts)
+}
+
+func (c *Client) ListServiceCtx(ctx context.Context, opts *ListSvcOpts) (*ServiceList, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	all := new(ServiceList)
+	v := url.Values{}
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("groupNameParam", opts.GroupName)
+	v.Add("serviceNameParam", opts.ServiceName)
+	v.Add("withInstances", "false")
+	v.Add("ignoreEmptyService", "false")
+	v.Add("pageSize", "100")
+	v.Add("accessToken", token)
+	for page := 1; ; page++ {
+		v.Set("pageNo", strconv.Itoa(page))
+		url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["list_svc"], v.Encode())
+		resp, err := c.get(ctx, url)
+		lst := new(ServiceList)
+		if c.APIVersion == "v1" {
+			// the v1 catalog api returns {"count": n, "serviceList": [...]}
+			var v1 struct {
+				Count int        `json:"count"`
+				Items []*Service `json:"serviceList"`
+			}
+			err = decode(resp, err, &v1)
+			lst.TotalCount, lst.Items = v1.Count, v1.Items
+		} else {
+			err = decodeResult(resp, err, lst)
+		}
+		if err != nil {
+			return nil, err
+		}
+		all.Items = append(all.Items, lst.Items...)
+		all.TotalCount = lst.TotalCount
+		if len(lst.Items) == 0 || len(all.Items) >= lst.TotalCount {
+			break
+		}
+	}
+	for _, svc := range all.Items {
+		if svc.NamespaceID == "" {
+			svc.NamespaceID = opts.NamespaceID
+		}
+	}
+	return all, nil
+}
+
+func (c *Client) GetService(opts *GetSvcOpts) (*Service, error) {
+	return c.GetServiceCtx(c.context(), opts)
+}
+
+func (c *Client) GetServiceCtx(ctx context.Context, opts *GetSvcOpts) (*Service, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("groupName", opts.GroupName)
+	v.Add("serviceName", opts.ServiceName)
+	v.Add("accessToken", token)
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["svc"], v.Encode())
+	resp, err := c.get(ctx, url)
+	svc := new(Service)
+	if err := c.decodeData(resp, err, svc); err != nil {
+		return nil, err
+	}
+	if svc.NamespaceID == "" {
+		svc.NamespaceID = opts.NamespaceID
+	}
+	return svc, nil
+}
+
+type CreateSvcOpts struct {
+	NamespaceID      string
+	GroupName        string
+	ServiceName      string
+	ProtectThreshold float64
+	Metadata         map[string]string
+	// Selector defaults to {"type": "none"}
+	Selector *Selector
+}
+
+func (c *Client) CreateService(opts *CreateSvcOpts) error {
+	return c.CreateServiceCtx(c.context(), opts)
+}
+
+func (c *Client) CreateServiceCtx(ctx context.Context, opts *CreateSvcOpts) error {
+	v, err := c.serviceValues(ctx, opts)
+	if err != nil {
+		return err
+	}
+	resp, err := c.postForm(ctx, c.URL+api[c.APIVersion]["svc"], v)
+	return checkErr(resp, err)
+}
+
+func (c *Client) UpdateService(opts *CreateSvcOpts) error {
+	return c.UpdateServiceCtx(c.context(), opts)
+}
+
+func (c *Client) UpdateServiceCtx(ctx context.Context, opts *CreateSvcOpts) error {
+	v, err := c.serviceValues(ctx, opts)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["svc"], v.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.do(req)
+	return checkErr(resp, err)
+}
+
+func (c *Client) serviceValues(ctx context.Context, opts *CreateSvcOpts) (url.Values, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selector := opts.Selector
+	if selector == nil {
+		selector = &Selector{Type: "none"}
+	}
+	s, err := json.Marshal(selector)
+	if err != nil {
+		return nil, err
+	}
+	metadata := []byte("{}")
+	if len(opts.Metadata) > 0 {
+		if metadata, err = json.Marshal(opts.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	v := url.Values{}
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("groupName", opts.GroupName)
+	v.Add("serviceName", opts.ServiceName)
+	v.Add("protectThreshold", strconv.FormatFloat(opts.ProtectThreshold, 'f', -1, 64))
+	v.Add("metadata", string(metadata))
+	v.Add("selector", string(s))
+	v.Add("accessToken", token)
+	return v, nil
+}
+
+type DeleteSvcOpts = GetSvcOpts
+
+func (c *Client) DeleteService(opts *DeleteSvcOpts) error {
+	return c.DeleteServiceCtx(c.context(), opts)
+}
+
+func (c *Client) DeleteServiceCtx(ctx context.Context, opts *DeleteSvcOpts) error {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return err
+	}
+	v := url.Values{}
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("groupName", opts.GroupName)
+	v.Add("serviceName", opts.ServiceName)
+	v.Add("accessToken", token)
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["svc"], v.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	return checkErr(resp, err)
+}
+
+type ListInstOpts struct {
+	NamespaceID string
+	GroupName   string
+	ServiceName string
+	// Clusters limits the instances to these clusters, all when empty
+	Clusters    []string
+	HealthyOnly bool
+}
+
+func (c *Client) ListInstance(opts *ListInstOpts) (*InstanceList, error) {
+	return c.ListInstanceCtx(c.context(), opts)
+}
+
+func (c *Client) ListInstanceCtx(ctx context.Context, opts *ListInstOpts) (*InstanceList, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Add("namespaceId", opts.NamespaceID)
+	v.Add("groupName", opts.GroupName)
+	v.Add("serviceName", opts.ServiceName)
+	v.Add("healthyOnly", strconv.FormatBool(opts.HealthyOnly))
+	v.Add("accessToken", token)
+	v.Add("pageSize", "100")
+	all := new(InstanceList)
+	if c.APIVersion == "v1" {
+		// the catalog api of the console returns the disabled instances
+		// too, but only for one cluster at a time and as {"count": n, "list": [...]}
+		clusters := opts.Clusters
+		if len(clusters) == 0 {
+			svc, err := c.GetServiceCtx(ctx, &GetSvcOpts{NamespaceID: opts.NamespaceID, GroupName: opts.GroupName, ServiceName: opts.ServiceName})
+			if err != nil {
+				return nil, err
+			}
+			for _, cluster := range svc.Clusters {
+				clusters = append(clusters, cluster.Name)
+			}
+		}
+		for _, cluster := range clusters {
+			v.Set("clusterName", cluster)
+			for page := 1; ; page++ {
+				v.Set("pageNo", strconv.Itoa(page))
+				url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["list_instance"], v.Encode())
+				resp, err := c.get(ctx, url)
+				var lst struct {
+					Count int         `json:"count"`
+					List  []*Instance `json:"list"`
+				}
+				if err := decode(resp, err, &lst); err != nil {
+					return nil, err
+				}
+				for _, inst := range lst.List {
+					if !opts.HealthyOnly || inst.Healthy {
+						all.Items = append(all.Items, inst)
+					}
+				}
+				if len(lst.List) == 0 || page*100 >= lst.Count {
+					break
+				}
+			}
+		}
+	} else {
+		v.Add("clusterName", strings.Join(opts.Clusters, ","))
+		for page := 1; ; page++ {
+			v.Set("pageNo", strconv.Itoa(page))
+			url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["list_instance"], v.Encode())
+			resp, err := c.get(ctx, url)
+			lst := new(InstanceList)
+			if err := decodeResult(resp, err, lst); err != nil {
+				return nil, err
+			}
+			all.Items = append(all.Items, lst.Items...)
+			if len(lst.Items) == 0 || len(all.Items) >= lst.TotalCount {
+				break
+			}
+		}
+	}
+	all.TotalCount = len(all.Items)
+	for _, inst := range all.Items {
+		inst.NamespaceID = opts.NamespaceID
+		inst.GroupName = opts.GroupName
+		if inst.ServiceName == "" {
+			inst.ServiceName = opts.ServiceName
+		}
+	}
+	return all, nil
+}
+
+// RegisterInstance registers inst to the service selected by its
+// NamespaceID, GroupName and ServiceName.
+func (c *Client) RegisterInstance(inst *Instance) error {
+	return c.RegisterInstanceCtx(c.context(), inst)
+}
+
+func (c *Client) RegisterInstanceCtx(ctx context.Context, inst *Instance) error {
+	v, err := c.instanceValues(ctx, inst)
+	if err != nil {
+		return err
+	}
+	resp, err := c.postForm(ctx, c.URL+api[c.APIVersion]["instance"], v)
+	return checkErr(resp, err)
+}
+
+// UpdateInstance replaces weight, enabled and metadata of inst on the
+// server, fields left empty are reset rather than kept.
+func (c *Client) UpdateInstance(inst *Instance) error {
+	return c.UpdateInstanceCtx(c.context(), inst)
+}
+
+func (c *Client) UpdateInstanceCtx(ctx context.Context, inst *Instance) error {
+	return c.doInstance(ctx, http.MethodPut, inst)
+}
+
+func (c *Client) DeregisterInstance(inst *Instance) error {
+	return c.DeregisterInstanceCtx(c.context(), inst)
+}
+
+func (c *Client) DeregisterInstanceCtx(ctx context.Context, inst *Instance) error {
+	return c.doInstance(ctx, http.MethodDelete, inst)
+}
+
+func (c *Client) doInstance(ctx context.Context, method string, inst *Instance) error {
+	v, err := c.instanceValues(ctx, inst)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s%s?%s", c.URL, api[c.APIVersion]["instance"], v.Encode())
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	return checkErr(resp, err)
+}
+
+func (c *Client) instanceValues(ctx context.Context, inst *Instance) (url.Values, error) {
+	token, err := c.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata := []byte("{}")
+	if len(inst.Metadata) > 0 {
+		if metadata, err = json.Marshal(inst.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	v := url.Values{}
+	v.Add("namespaceId", inst.NamespaceID)
+	v.Add("groupName", inst.GroupName)
+	v.Add("serviceName", inst.GetServiceName())
+	v.Add("clusterName", inst.ClusterName)
+	v.Add("ip", inst.IP)
+	v.Add("port", strconv.Itoa(inst.Port))
+	v.Add("weight", strconv.FormatFloat(inst.Weight, 'f', -1, 64))
+	v.Add("enabled", strconv.FormatBool(inst.Enabled))
+	v.Add("healthy", strconv.FormatBool(inst.Healthy))
+	v.Add("ephemeral", strconv.FormatBool(inst.Ephemeral))
+	v.Add("metadata", string(metadata))
+	v.Add("accessToken", token)
+	return v, nil
+}
@@ -0,0 +1,203 @@
+package nacos
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var service = `{"name": "DEFAULT_GROUP@@svc1", "groupName": "DEFAULT_GROUP", "namespaceId": "test", "protectThreshold": 0.5, "metadata": {"k": "v"}, "selector": {"type": "none"}, "clusters": [{"name": "DEFAULT"}]}`
+var serviceV3 = newV3Data(service)
+var svcList = `{"count": 1, "serviceList": [{"name": "svc1", "groupName": "DEFAULT_GROUP", "clusterCount": 1, "ipCount": 2, "healthyInstanceCount": 1}]}`
+var svcListV3 = newV3Data(newV1Data(`{"name": "svc1", "groupName": "DEFAULT_GROUP", "clusterCount": 1, "ipCount": 2, "healthyInstanceCount": 1}`))
+var instance = `{"instanceId": "10.0.0.1#8080#DEFAULT#DEFAULT_GROUP@@svc1", "ip": "10.0.0.1", "port": 8080, "weight": 1.0, "healthy": true, "enabled": true, "ephemeral": true, "clusterName": "DEFAULT", "serviceName": "DEFAULT_GROUP@@svc1", "metadata": {"k": "v"}}`
+var instList = fmt.Sprintf(`{"count": 1, "list": [%s]}`, instance)
+var instListV3 = newV3Data(newV1Data(instance))
+
+func TestListService(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			svcs, err := c.ListService(&ListSvcOpts{NamespaceID: "test"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, 1, svcs.TotalCount)
+				assert.Equal(t, "svc1", svcs.Items[0].GetName())
+				assert.Equal(t, "test", svcs.Items[0].NamespaceID)
+				assert.Equal(t, 2, svcs.Items[0].IpCount)
+			}
+		})
+	}
+}
+
+func TestGetService(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			svc, err := c.GetService(&GetSvcOpts{NamespaceID: "test", GroupName: "DEFAULT_GROUP", ServiceName: "svc1"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "svc1", svc.GetName())
+				assert.Equal(t, 0.5, svc.ProtectThreshold)
+				assert.Equal(t, map[string]string{"k": "v"}, svc.Metadata)
+			}
+		})
+	}
+}
+
+func TestCreateService(t *testing.T) {
+	var form url.Values
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		if r.Method == http.MethodPut {
+			form = r.URL.Query()
+		}
+		w.Write([]byte(`ok`))
+	})
+	defer ts.Close()
+	opts := &CreateSvcOpts{NamespaceID: "test", GroupName: "DEFAULT_GROUP", ServiceName: "svc1", ProtectThreshold: 0.5, Metadata: map[string]string{"k": "v"}}
+	if assert.NoError(t, c.CreateService(opts)) {
+		assert.Equal(t, "0.5", form.Get("protectThreshold"))
+		assert.Equal(t, `{"k":"v"}`, form.Get("metadata"))
+		assert.Equal(t, `{"type":"none"}`, form.Get("selector"))
+	}
+	opts.Selector = &Selector{Type: "label", Expression: "CONSUMER.label.a = PROVIDER.label.a"}
+	if assert.NoError(t, c.UpdateService(opts)) {
+		assert.Equal(t, "svc1", form.Get("serviceName"))
+		assert.Equal(t, `{"type":"label","expression":"CONSUMER.label.a = PROVIDER.label.a"}`, form.Get("selector"))
+	}
+}
+
+func TestDeleteService(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			err := c.DeleteService(&DeleteSvcOpts{NamespaceID: "test", GroupName: "DEFAULT_GROUP", ServiceName: "svc1"})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestListInstance(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			insts, err := c.ListInstance(&ListInstOpts{NamespaceID: "test", GroupName: "DEFAULT_GROUP", ServiceName: "svc1"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, 1, insts.TotalCount)
+				inst := insts.Items[0]
+				assert.Equal(t, "10.0.0.1", inst.IP)
+				assert.Equal(t, 8080, inst.Port)
+				assert.Equal(t, "svc1", inst.GetServiceName())
+				assert.Equal(t, "test", inst.NamespaceID)
+				assert.True(t, inst.Healthy)
+			}
+		})
+	}
+}
+
+func TestListInstanceDisabled(t *testing.T) {
+	disabled := `{"ip": "10.0.0.2", "port": 8080, "weight": 1.0, "healthy": false, "enabled": false, "clusterName": "DEFAULT"}`
+	var query url.Values
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/ns/service":
+			w.Write([]byte(service))
+		case "/v1/ns/catalog/instances":
+			query = r.URL.Query()
+			fmt.Fprintf(w, `{"count": 2, "list": [%s, %s]}`, instance, disabled)
+		}
+	})
+	defer ts.Close()
+	insts, err := c.ListInstance(&ListInstOpts{NamespaceID: "test", GroupName: "DEFAULT_GROUP", ServiceName: "svc1", Clusters: []string{"DEFAULT"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "DEFAULT", query.Get("clusterName"))
+		assert.Equal(t, "1", query.Get("pageNo"))
+		if assert.Equal(t, 2, insts.TotalCount) {
+			assert.Equal(t, "10.0.0.2", insts.Items[1].IP)
+			assert.False(t, insts.Items[1].Enabled)
+		}
+	}
+	insts, err = c.ListInstance(&ListInstOpts{NamespaceID: "test", GroupName: "DEFAULT_GROUP", ServiceName: "svc1", HealthyOnly: true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, insts.TotalCount)
+	}
+}
+
+func TestListInstanceAllClusters(t *testing.T) {
+	other := `{"ip": "10.0.0.3", "port": 8080, "weight": 1.0, "healthy": true, "enabled": true, "clusterName": "other"}`
+	var clusters []string
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/ns/service":
+			w.Write([]byte(`{"name": "DEFAULT_GROUP@@svc1", "groupName": "DEFAULT_GROUP", "clusters": [{"name": "DEFAULT"}, {"name": "other"}]}`))
+		case "/v1/ns/catalog/instances":
+			// the catalog api matches the exact cluster name
+			cluster := r.URL.Query().Get("clusterName")
+			clusters = append(clusters, cluster)
+			switch cluster {
+			case "DEFAULT":
+				fmt.Fprintf(w, `{"count": 1, "list": [%s]}`, instance)
+			case "other":
+				fmt.Fprintf(w, `{"count": 1, "list": [%s]}`, other)
+			default:
+				w.Write([]byte(`{"count": 0, "list": []}`))
+			}
+		}
+	})
+	defer ts.Close()
+	insts, err := c.ListInstance(&ListInstOpts{NamespaceID: "test", GroupName: "DEFAULT_GROUP", ServiceName: "svc1"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"DEFAULT", "other"}, clusters)
+		if assert.Equal(t, 2, insts.TotalCount) {
+			assert.Equal(t, "10.0.0.1", insts.Items[0].IP)
+			assert.Equal(t, "DEFAULT", insts.Items[0].ClusterName)
+			assert.Equal(t, "10.0.0.3", insts.Items[1].IP)
+		}
+	}
+}
+
+func TestRegisterInstance(t *testing.T) {
+	ts, c := startServer()
+	defer ts.Close()
+	inst := &Instance{IP: "10.0.0.1", Port: 8080, Weight: 1, Enabled: true, ServiceName: "svc1", GroupName: "DEFAULT_GROUP", NamespaceID: "test"}
+	for _, tt := range apiTests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			c.APIVersion = tt.apiVersion
+			assert.NoError(t, c.RegisterInstance(inst))
+			assert.NoError(t, c.UpdateInstance(inst))
+			assert.NoError(t, c.DeregisterInstance(inst))
+		})
+	}
+}
+
+func TestUpdateInstance(t *testing.T) {
+	var query url.Values
+	ts, c := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/ns/instance" {
+			assert.Equal(t, http.MethodPut, r.Method)
+			query = r.URL.Query()
+		}
+		w.Write([]byte(`ok`))
+	})
+	defer ts.Close()
+	inst := &Instance{IP: "10.0.0.1", Port: 8080, Weight: 0.5, ServiceName: "DEFAULT_GROUP@@svc1", GroupName: "DEFAULT_GROUP", Metadata: map[string]string{"k": "v"}}
+	if assert.NoError(t, c.UpdateInstance(inst)) {
+		assert.Equal(t, "svc1", query.Get("serviceName"))
+		assert.Equal(t, "8080", query.Get("port"))
+		assert.Equal(t, "0.5", query.Get("weight"))
+		assert.Equal(t, "false", query.Get("enabled"))
+		assert.Equal(t, `{"k":"v"}`, query.Get("metadata"))
+	}
+}
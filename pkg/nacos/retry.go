@@ -0,0 +1,227 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nacos
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RetryPolicy tells how many times and how long apart a failed request is
+// sent again. GET and DELETE requests are retried on network errors and on
+// 502, 503 and 504 responses, the other ones only when the connection to
+// the server could not be made.
+type RetryPolicy struct {
+	MaxRetries int `json:"maxRetries"`
+	// InitialBackoff is the wait before the first retry, it doubles at every
+	// retry up to MaxBackoff. A random jitter of up to half of it is removed.
+	InitialBackoff time.Duration `json:"initialBackoff"`
+	MaxBackoff     time.Duration `json:"maxBackoff"`
+}
+
+// DefaultRetryPolicy is the policy of a Client created without WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// Backoff returns how long to wait before the retry number n, from 0.
+func (p *RetryPolicy) Backoff(n int) time.Duration {
+	d := p.InitialBackoff
+	for i := 0; i < n && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d - rand.N(d/2+1)
+}
+
+// WithURLs gives the other nodes of the cluster, the client moves to the
+// next one when a node can't be reached.
+func WithURLs(urls ...string) Option {
+	return func(c *Client) {
+		c.URLs = urls
+	}
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = &p
+	}
+}
+
+// WithLogger logs the retries and the changes of node to l.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.Logger = l
+	}
+}
+
+// Logger is implemented by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+func (c *Client) logf(format string, v ...any) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+	}
+}
+
+func (c *Client) retryPolicy() *RetryPolicy {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	}
+	return &DefaultRetryPolicy
+}
+
+// do sends req with retries and failover. A stored token the server refuses
+// is dropped and req is sent once more with a new one.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.doWith(c.HTTPClient(), req)
+}
+
+// doWith is do sending req with hc, e.g. with a longer timeout.
+func (c *Client) doWith(hc *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := c.send(hc, req)
+	if err != nil || !c.storedToken || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+		return resp, err
+	}
+	next, err := c.renewToken(req)
+	if next == nil && err == nil {
+		return resp, nil
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	c.logf("%s %s: %s, login again", req.Method, redact(req.URL), resp.Status)
+	return c.send(hc, next)
+}
+
+func (c *Client) send(hc *http.Client, req *http.Request) (*http.Response, error) {
+	policy := c.retryPolicy()
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodDelete
+	for n := 0; ; n++ {
+		resp, err := hc.Do(req)
+		retry, reason := false, ""
+		switch {
+		case err != nil:
+			retry = req.Context().Err() == nil && (idempotent || isDialError(err))
+			reason = err.Error()
+		case idempotent && isTransientStatus(resp.StatusCode):
+			retry = true
+			reason = resp.Status
+		}
+		if !retry || n >= policy.MaxRetries {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+			resp.Body.Close()
+		}
+		failed := redact(req.URL)
+		wait := policy.Backoff(n)
+		if err != nil && c.nextURL() {
+			next, rerr := c.rebase(req)
+			if rerr != nil {
+				return nil, err
+			}
+			req = next
+			// the other nodes are tried at once before waiting
+			if n < len(c.URLs)-1 {
+				wait = 0
+			}
+		}
+		c.logf("%s %s: %s, retry %d/%d on %s in %s", req.Method, failed, reason, n+1, policy.MaxRetries, c.URL, wait.Round(time.Millisecond))
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// nextURL moves c to the node after the current one, it reports false when
+// there is no other node.
+func (c *Client) nextURL() bool {
+	if len(c.URLs) < 2 {
+		return false
+	}
+	i := 0
+	for j, u := range c.URLs {
+		if u == c.URL {
+			i = j
+			break
+		}
+	}
+	c.prevURL = c.URL
+	c.URL = c.URLs[(i+1)%len(c.URLs)]
+	return true
+}
+
+// rebase returns req sent to the current node instead of the previous one.
+func (c *Client) rebase(req *http.Request) (*http.Request, error) {
+	path, ok := strings.CutPrefix(req.URL.String(), c.prevURL)
+	if !ok {
+		return req, nil
+	}
+	u, err := url.Parse(c.URL + path)
+	if err != nil {
+		return nil, err
+	}
+	next := req.Clone(req.Context())
+	next.URL = u
+	next.Host = ""
+	return next, nil
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isTransientStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// redact removes the query of u which may hold the access token.
+func redact(u *url.URL) string {
+	r := *u
+	r.RawQuery = ""
+	return r.String()
+}
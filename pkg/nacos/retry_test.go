@@ -0,0 +1,90 @@
+package nacos
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fastRetry = RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for n, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		d := p.Backoff(n)
+		assert.LessOrEqual(t, d, max)
+		assert.GreaterOrEqual(t, d, max/2)
+	}
+	assert.Equal(t, time.Duration(0), (&RetryPolicy{}).Backoff(3))
+}
+
+func TestRetryTransientStatus(t *testing.T) {
+	calls := map[string]int{}
+	ts, _ := startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/console/namespaces", "/v1/auth/users":
+			calls[r.Method]++
+			if calls[r.Method] < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(nsList))
+		}
+	})
+	defer ts.Close()
+	logger := &testLogger{}
+	c := &Client{URL: ts.URL, User: "user", Password: "password", APIVersion: "v1", RetryPolicy: &fastRetry, Logger: logger}
+
+	_, err := c.ListNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls[http.MethodGet])
+	if assert.Len(t, logger.lines, 2) {
+		assert.Contains(t, logger.lines[0], "503 Service Unavailable, retry 1/3")
+		assert.NotContains(t, logger.lines[0], "accessToken")
+	}
+
+	// creating is not idempotent
+	err = c.CreateUser("user", "password")
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls[http.MethodPost])
+
+	c.RetryPolicy = &RetryPolicy{MaxRetries: 1, InitialBackoff: time.Millisecond}
+	calls = map[string]int{}
+	_, err = c.ListNamespace()
+	assert.ErrorContains(t, err, "503")
+	assert.Equal(t, 2, calls[http.MethodGet])
+}
+
+func TestFailover(t *testing.T) {
+	ts, _ := startServer()
+	defer ts.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	logger := &testLogger{}
+
+	c := NewClient(down.URL, "user", "password", WithURLs(down.URL, ts.URL), WithRetryPolicy(fastRetry), WithLogger(logger))
+	assert.Equal(t, ts.URL, c.URL)
+	assert.Equal(t, "v3", c.APIVersion)
+	if assert.NotEmpty(t, logger.lines) {
+		assert.True(t, strings.HasSuffix(logger.lines[0], "on "+ts.URL+" in 0s"), logger.lines[0])
+	}
+
+	// a post which could not connect is sent to the next node too
+	c.URL = down.URL
+	assert.NoError(t, c.CreateNamespace(&CreateNsOpts{ID: "test"}))
+	assert.Equal(t, ts.URL, c.URL)
+}
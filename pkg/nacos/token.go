@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nacos
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tokenRefreshWindow is how long before its expiration a token is renewed,
+// so that it does not expire in the middle of a command.
+const tokenRefreshWindow = 5 * time.Minute
+
+// TokenStore keeps a token between clients, e.g. between runs of a command.
+type TokenStore interface {
+	// Load returns the saved token, nil if there is none.
+	Load() (*Token, error)
+	Save(token *Token) error
+	Delete() error
+}
+
+// FileTokenStore keeps the token in a json file only readable by its owner.
+type FileTokenStore struct {
+	Path string
+}
+
+func (s *FileTokenStore) Load() (*Token, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	token := new(Token)
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *FileTokenStore) Save(token *Token) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+func (s *FileTokenStore) Delete() error {
+	err := os.Remove(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// renewToken deletes the stored token, logs in and returns req with the new
+// token, in its query or in its form. It returns nil when req has no token.
+func (c *Client) renewToken(req *http.Request) (*http.Request, error) {
+	query := req.URL.Query()
+	var form url.Values
+	if !query.Has("accessToken") {
+		if req.GetBody == nil || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			return nil, nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+		form, err = url.ParseQuery(string(data))
+		if err != nil || !form.Has("accessToken") {
+			return nil, nil
+		}
+	}
+	c.Token = nil
+	c.storedToken = false
+	if err := c.TokenStore.Delete(); err != nil {
+		return nil, err
+	}
+	if err := c.LoginCtx(req.Context()); err != nil {
+		return nil, err
+	}
+	next := req.Clone(req.Context())
+	if form == nil {
+		query.Set("accessToken", c.AccessToken)
+		next.URL.RawQuery = query.Encode()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			next.Body = body
+		}
+		return next, nil
+	}
+	form.Set("accessToken", c.AccessToken)
+	body := form.Encode()
+	next.Body = io.NopCloser(strings.NewReader(body))
+	next.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(body)), nil
+	}
+	next.ContentLength = int64(len(body))
+	return next, nil
+}
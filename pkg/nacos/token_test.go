@@ -0,0 +1,148 @@
+package nacos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	s := &FileTokenStore{Path: filepath.Join(t.TempDir(), "tokens", "test.json")}
+	token, err := s.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+
+	want := &Token{AccessToken: "test-token", TokenTTL: 3600, ExpiredAt: 1700000000}
+	assert.NoError(t, s.Save(want))
+	token, err = s.Load()
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, token)
+	}
+
+	assert.NoError(t, s.Delete())
+	assert.NoError(t, s.Delete())
+	token, err = s.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestGetTokenFromStore(t *testing.T) {
+	logins := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/login" {
+			logins++
+			w.Write([]byte(`{"accessToken": "new-token", "tokenTtl": 3600}`))
+		}
+	}))
+	defer ts.Close()
+	store := &FileTokenStore{Path: filepath.Join(t.TempDir(), "test.json")}
+	tests := []struct {
+		name   string
+		saved  *Token
+		want   string
+		logins int
+	}{
+		{"no token", nil, "new-token", 1},
+		{"valid", &Token{AccessToken: "saved-token", ExpiredAt: time.Now().Add(time.Hour).Unix()}, "saved-token", 0},
+		{"about to expire", &Token{AccessToken: "saved-token", ExpiredAt: time.Now().Add(time.Minute).Unix()}, "new-token", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, store.Delete())
+			if tt.saved != nil {
+				assert.NoError(t, store.Save(tt.saved))
+			}
+			logins = 0
+			c := NewClient(ts.URL, "user", "password")
+			c.APIVersion = "v1"
+			c.TokenStore = store
+			token, err := c.GetToken()
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, token)
+				assert.Equal(t, tt.logins, logins)
+				saved, _ := store.Load()
+				assert.Equal(t, tt.want, saved.AccessToken)
+			}
+		})
+	}
+}
+
+func TestLoginPasswordFunc(t *testing.T) {
+	var password string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/login" {
+			password = r.FormValue("password")
+			w.Write([]byte(`{"accessToken": "test-token", "tokenTtl": 3600}`))
+		}
+	}))
+	defer ts.Close()
+	c := NewClient(ts.URL, "user", "")
+	c.APIVersion = "v1"
+	calls := 0
+	c.PasswordFunc = func() (string, error) {
+		calls++
+		return "secret", nil
+	}
+	assert.NoError(t, c.Login())
+	assert.NoError(t, c.Login())
+	assert.Equal(t, "secret", password)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRevokedStoredToken(t *testing.T) {
+	logins := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/auth/login":
+			logins++
+			w.Write([]byte(`{"accessToken": "new-token", "tokenTtl": 3600}`))
+		case r.FormValue("accessToken") != "new-token":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code": 403, "message": "token invalid!"}`))
+		case r.URL.Path == "/v1/console/namespaces":
+			w.Write([]byte(nsList))
+		}
+	}))
+	defer ts.Close()
+	store := &FileTokenStore{Path: filepath.Join(t.TempDir(), "test.json")}
+	stale := &Token{AccessToken: "revoked-token", ExpiredAt: time.Now().Add(time.Hour).Unix()}
+	tests := []struct {
+		name string
+		call func(c *Client) error
+	}{
+		{"query", func(c *Client) error {
+			_, err := c.ListNamespace()
+			return err
+		}},
+		{"form", func(c *Client) error {
+			return c.CreateConfig(&CreateCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", Content: "test"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, store.Save(stale))
+			logins = 0
+			c := NewClient(ts.URL, "user", "password")
+			c.APIVersion = "v1"
+			c.TokenStore = store
+			assert.NoError(t, tt.call(c))
+			assert.Equal(t, 1, logins)
+			saved, _ := store.Load()
+			assert.Equal(t, "new-token", saved.AccessToken)
+		})
+	}
+
+	t.Run("refused again", func(t *testing.T) {
+		logins = 0
+		c := NewClient(ts.URL, "user", "password")
+		c.APIVersion = "v1"
+		c.Token = &Token{AccessToken: "fresh-token", ExpiredAt: time.Now().Add(time.Hour).Unix()}
+		_, err := c.ListNamespace()
+		assert.True(t, IsUnauthorized(err))
+		assert.Equal(t, 0, logins)
+	})
+}
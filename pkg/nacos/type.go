@@ -16,10 +16,13 @@ limitations under the License.
 package nacos
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type NamespaceList struct {
@@ -61,6 +64,11 @@ type ConfigurationV3 struct {
 	Data    *Configuration `json:"data"`
 }
 
+type BetaConfiguration struct {
+	Configuration
+	BetaIps string `json:"betaIps"`
+}
+
 func (c *Configuration) GetGroup() string {
 	if c.Group != "" {
 		return c.Group
@@ -75,6 +83,217 @@ func (c *Configuration) GetNamespace() string {
 	return c.NamespaceID
 }
 
+// Timestamp is a time that nacos encodes either as epoch milliseconds or as
+// a formatted string, depending on the server version.
+type Timestamp string
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = ""
+		return nil
+	}
+	*t = Timestamp(strings.Trim(string(data), `"`))
+	return nil
+}
+
+func (t Timestamp) String() string {
+	if ms, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+		return time.UnixMilli(ms).Format(time.RFC3339)
+	}
+	return string(t)
+}
+
+type ConfigHistory struct {
+	ID               json.Number `json:"id"`
+	LastID           json.Number `json:"lastId"`
+	DataID           string      `json:"dataId"`
+	Group            string      `json:"group"`
+	GroupName        string      `json:"groupName"`
+	Tenant           string      `json:"tenant"`
+	NamespaceID      string      `json:"namespaceId"`
+	Application      string      `json:"appName,omitempty"`
+	Md5              string      `json:"md5,omitempty"`
+	Content          string      `json:"content,omitempty"`
+	SrcIP            string      `json:"srcIp,omitempty"`
+	SrcUser          string      `json:"srcUser,omitempty"`
+	OpType           string      `json:"opType"`
+	CreatedTime      Timestamp   `json:"createdTime,omitempty"`
+	LastModifiedTime Timestamp   `json:"lastModifiedTime,omitempty"`
+	CreateTime       Timestamp   `json:"createTime,omitempty"`
+	ModifyTime       Timestamp   `json:"modifyTime,omitempty"`
+}
+
+func (h *ConfigHistory) GetGroup() string {
+	if h.Group != "" {
+		return h.Group
+	}
+	return h.GroupName
+}
+
+func (h *ConfigHistory) GetNamespace() string {
+	if h.Tenant != "" {
+		return h.Tenant
+	}
+	return h.NamespaceID
+}
+
+func (h *ConfigHistory) GetModifyTime() Timestamp {
+	if h.LastModifiedTime != "" {
+		return h.LastModifiedTime
+	}
+	return h.ModifyTime
+}
+
+// ConflictPolicy tells what to do when an imported or cloned configuration
+// already exists, the values are the ones of the nacos console.
+type ConflictPolicy string
+
+const (
+	PolicyAbort     ConflictPolicy = "ABORT"
+	PolicySkip      ConflictPolicy = "SKIP"
+	PolicyOverwrite ConflictPolicy = "OVERWRITE"
+)
+
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	p := ConflictPolicy(strings.ToUpper(s))
+	switch p {
+	case PolicyAbort, PolicySkip, PolicyOverwrite:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid policy %q, must be one of abort, skip, overwrite", s)
+}
+
+type CloneResult struct {
+	SuccCount int              `json:"succCount"`
+	SkipCount int              `json:"skipCount"`
+	SkipData  []*Configuration `json:"skipData"`
+	FailData  []*Configuration `json:"failData"`
+}
+
+type Selector struct {
+	Type       string `json:"type"`
+	Expression string `json:"expression,omitempty"`
+}
+
+type Service struct {
+	Name                 string            `json:"name"`
+	ServiceName          string            `json:"serviceName,omitempty"`
+	GroupName            string            `json:"groupName"`
+	NamespaceID          string            `json:"namespaceId,omitempty"`
+	ProtectThreshold     float64           `json:"protectThreshold"`
+	Metadata             map[string]string `json:"metadata,omitempty"`
+	Selector             *Selector         `json:"selector,omitempty"`
+	ClusterCount         int               `json:"clusterCount,omitempty"`
+	IpCount              int               `json:"ipCount,omitempty"`
+	HealthyInstanceCount int               `json:"healthyInstanceCount,omitempty"`
+	// Clusters is only in the detail of a service on v1
+	Clusters []*Cluster `json:"clusters,omitempty"`
+}
+
+type Cluster struct {
+	Name string `json:"name"`
+}
+
+// GetName returns the name of the service without the "group@@" prefix
+// some apis add to it.
+func (s *Service) GetName() string {
+	name := s.Name
+	if name == "" {
+		name = s.ServiceName
+	}
+	if _, after, ok := strings.Cut(name, "@@"); ok {
+		return after
+	}
+	return name
+}
+
+type ServiceList struct {
+	TotalCount int        `json:"totalCount"`
+	Items      []*Service `json:"pageItems"`
+}
+
+type Instance struct {
+	InstanceID  string            `json:"instanceId,omitempty"`
+	IP          string            `json:"ip"`
+	Port        int               `json:"port"`
+	Weight      float64           `json:"weight"`
+	Healthy     bool              `json:"healthy"`
+	Enabled     bool              `json:"enabled"`
+	Ephemeral   bool              `json:"ephemeral"`
+	ClusterName string            `json:"clusterName"`
+	ServiceName string            `json:"serviceName"`
+	GroupName   string            `json:"groupName,omitempty"`
+	NamespaceID string            `json:"namespaceId,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// GetServiceName returns the name of the service without the "group@@"
+// prefix some apis add to it.
+func (i *Instance) GetServiceName() string {
+	if _, after, ok := strings.Cut(i.ServiceName, "@@"); ok {
+		return after
+	}
+	return i.ServiceName
+}
+
+type InstanceList struct {
+	TotalCount int         `json:"totalCount"`
+	Items      []*Instance `json:"pageItems"`
+}
+
+type RaftGroup struct {
+	Leader          string   `json:"leader"`
+	RaftGroupMember []string `json:"raftGroupMember"`
+	Term            int64    `json:"term"`
+}
+
+type NodeExtendInfo struct {
+	LastRefreshTime Timestamp `json:"lastRefreshTime"`
+	RaftPort        string    `json:"raftPort"`
+	Version         string    `json:"version"`
+	ReadyToUpgrade  bool      `json:"readyToUpgrade"`
+	RaftMetaData    struct {
+		MetaDataMap map[string]RaftGroup `json:"metaDataMap"`
+	} `json:"raftMetaData"`
+}
+
+type Node struct {
+	IP            string         `json:"ip"`
+	Port          int            `json:"port"`
+	State         string         `json:"state"`
+	Address       string         `json:"address"`
+	FailAccessCnt int            `json:"failAccessCnt"`
+	ExtendInfo    NodeExtendInfo `json:"extendInfo"`
+}
+
+// RaftRole returns LEADER when the node leads every raft group, FOLLOWER
+// when it leads none, or how many groups it leads, e.g. "LEADER 1/3".
+func (n *Node) RaftRole() string {
+	groups := n.ExtendInfo.RaftMetaData.MetaDataMap
+	if len(groups) == 0 {
+		return ""
+	}
+	self := n.IP + ":" + n.ExtendInfo.RaftPort
+	leads := 0
+	for _, g := range groups {
+		if g.Leader == self {
+			leads++
+		}
+	}
+	switch leads {
+	case 0:
+		return "FOLLOWER"
+	case len(groups):
+		return "LEADER"
+	default:
+		return fmt.Sprintf("LEADER %d/%d", leads, len(groups))
+	}
+}
+
+type NodeList struct {
+	Items []*Node
+}
+
 type User struct {
 	Name     string `json:"username"`
 	Password string `json:"password"`
@@ -92,7 +311,7 @@ type Permission struct {
 }
 
 type ListTypes interface {
-	User | Role | Permission | Configuration
+	User | Role | Permission | Configuration | ConfigHistory
 }
 
 type List[T ListTypes] struct {
@@ -124,6 +343,7 @@ func (lst List[T]) All() []*T {
 }
 
 type ConfigurationList = List[Configuration]
+type ConfigHistoryList = List[ConfigHistory]
 type PermissionList = List[Permission]
 type RoleList = List[Role]
 type UserList = List[User]
@@ -147,6 +367,7 @@ func (lst ListV3[T]) IsEnd() bool {
 }
 
 type ConfigurationListV3 = ListV3[Configuration]
+type ConfigHistoryListV3 = ListV3[ConfigHistory]
 type PermissionListV3 = ListV3[Permission]
 type RoleListV3 = ListV3[Role]
 type UserListV3 = ListV3[User]
@@ -157,13 +378,16 @@ type Paginator[T any] interface {
 	IsEnd() bool
 }
 
-func listResource[L Paginator[T], T ListTypes](c *Client, endpoint string) (*List[T], error) {
-	token, err := c.GetToken()
+func listResource[L Paginator[T], T ListTypes](ctx context.Context, c *Client, endpoint string, params url.Values) (*List[T], error) {
+	token, err := c.GetTokenCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	all := new(List[T])
 	v := url.Values{}
+	for k, vs := range params {
+		v[k] = vs
+	}
 	v.Add("search", "accurate")
 	v.Add("accessToken", token)
 	v.Add("pageNo", "1")
@@ -171,7 +395,7 @@ func listResource[L Paginator[T], T ListTypes](c *Client, endpoint string) (*Lis
 	for {
 		var lst L
 		url := fmt.Sprintf("%s%s?%s", c.URL, endpoint, v.Encode())
-		resp, err := http.Get(url)
+		resp, err := c.get(ctx, url)
 		if err := decode(resp, err, &lst); err != nil {
 			return nil, err
 		}
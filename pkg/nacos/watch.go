@@ -0,0 +1,188 @@
+/*
+Copyright © 2025 Joe Lee <lj_2005@163.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nacos
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	wordSeparator = "\x02"
+	lineSeparator = "\x01"
+)
+
+// Watcher long-polls the nacos listener api for changes of a set of
+// configurations, all of them are sent in a single request.
+type Watcher struct {
+	client *Client
+	// Timeout is how long the server holds a poll when nothing changes.
+	Timeout time.Duration
+	items   []*watchItem
+}
+
+type watchItem struct {
+	opts GetCfgOpts
+	md5  string
+}
+
+func (c *Client) NewWatcher() *Watcher {
+	return &Watcher{client: c, Timeout: 30 * time.Second}
+}
+
+// Add starts watching the configuration, md5 is the digest of the content
+// already known by the caller, empty if the configuration does not exist.
+func (w *Watcher) Add(opts *GetCfgOpts, md5 string) {
+	w.items = append(w.items, &watchItem{opts: *opts, md5: md5})
+}
+
+// Poll sends one long-polling request and returns the configurations the
+// server reported as changed, it returns nil when the poll timed out.
+func (w *Watcher) Poll() ([]*GetCfgOpts, error) {
+	return w.PollCtx(w.client.context())
+}
+
+func (w *Watcher) PollCtx(ctx context.Context) ([]*GetCfgOpts, error) {
+	token, err := w.client.GetTokenCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	for _, it := range w.items {
+		sb.WriteString(it.opts.DataID + wordSeparator + it.opts.Group + wordSeparator + it.md5)
+		if it.opts.NamespaceID != "" {
+			sb.WriteString(wordSeparator + it.opts.NamespaceID)
+		}
+		sb.WriteString(lineSeparator)
+	}
+	v := url.Values{}
+	v.Add("Listening-Configs", sb.String())
+	v.Add("accessToken", token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.client.URL+api[w.client.APIVersion]["listener"], strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Long-Pulling-Timeout", strconv.FormatInt(w.Timeout.Milliseconds(), 10))
+	resp, err := w.client.doWith(w.httpClient(), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseChangedConfigs(string(data))
+}
+
+// Watch polls until fn or a request returns an error. fn is called with the
+// new state of every changed configuration, a deleted configuration is
+// passed with empty Content and Md5.
+func (w *Watcher) Watch(fn func(*Configuration) error) error {
+	return w.WatchCtx(w.client.context(), fn)
+}
+
+// WatchCtx is Watch stopping with the error of ctx when it is done.
+func (w *Watcher) WatchCtx(ctx context.Context, fn func(*Configuration) error) error {
+	for {
+		changed, err := w.PollCtx(ctx)
+		if err != nil {
+			return err
+		}
+		for _, opts := range changed {
+			cfg, err := w.client.GetConfigCtx(ctx, opts)
+			if err != nil && !IsNotFound(err) {
+				return err
+			}
+			if cfg == nil {
+				cfg = &Configuration{DataID: opts.DataID, Group: opts.Group, NamespaceID: opts.NamespaceID}
+			}
+			w.update(opts, ContentMd5(cfg))
+			if err := fn(cfg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *Watcher) update(opts *GetCfgOpts, md5 string) {
+	for _, it := range w.items {
+		if it.opts == *opts {
+			it.md5 = md5
+		}
+	}
+}
+
+// pollMargin is how long a poll may take beyond Timeout before the client
+// gives up, the server answers a little after Timeout when nothing changes.
+const pollMargin = 10 * time.Second
+
+// httpClient returns the client of the Watcher with a timeout long enough
+// for the server to hold the poll.
+func (w *Watcher) httpClient() *http.Client {
+	hc := w.client.HTTPClient()
+	if hc.Timeout == 0 || hc.Timeout >= w.Timeout+pollMargin {
+		return hc
+	}
+	long := *hc
+	long.Timeout = w.Timeout + pollMargin
+	return &long
+}
+
+func parseChangedConfigs(data string) ([]*GetCfgOpts, error) {
+	data, err := url.QueryUnescape(strings.TrimSpace(data))
+	if err != nil {
+		return nil, err
+	}
+	var changed []*GetCfgOpts
+	for _, line := range strings.Split(data, lineSeparator) {
+		if line == "" {
+			continue
+		}
+		words := strings.Split(line, wordSeparator)
+		if len(words) < 2 {
+			return nil, fmt.Errorf("invalid listener response %q", line)
+		}
+		opts := &GetCfgOpts{DataID: words[0], Group: words[1]}
+		if len(words) > 2 {
+			opts.NamespaceID = words[2]
+		}
+		changed = append(changed, opts)
+	}
+	return changed, nil
+}
+
+// ContentMd5 returns the md5 of the configuration as nacos computes it, an
+// empty string for a configuration without content.
+func ContentMd5(cfg *Configuration) string {
+	if cfg.Md5 != "" || cfg.Content == "" {
+		return cfg.Md5
+	}
+	sum := md5.Sum([]byte(cfg.Content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,114 @@
+package nacos
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func startWatchServer(t *testing.T) (*httptest.Server, *Client) {
+	return startHandlerServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/cs/configs/listener":
+			assert.Equal(t, "30000", r.Header.Get("Long-Pulling-Timeout"))
+			assert.Equal(t, "test\x02DEFAULT_GROUP\x02old-md5\x02test-tenant\x01", r.FormValue("Listening-Configs"))
+			w.Write([]byte(url.QueryEscape("test\x02DEFAULT_GROUP\x02test-tenant\x01") + "\n"))
+		case "/v1/cs/configs":
+			w.Write([]byte(config))
+		}
+	})
+}
+
+func TestWatcherPoll(t *testing.T) {
+	ts, c := startWatchServer(t)
+	defer ts.Close()
+	w := c.NewWatcher()
+	w.Add(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"}, "old-md5")
+	changed, err := w.Poll()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*GetCfgOpts{{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"}}, changed)
+	}
+}
+
+func TestWatcherPollFailover(t *testing.T) {
+	ts, c := startWatchServer(t)
+	defer ts.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	c.URLs = []string{down.URL, ts.URL}
+	c.URL = down.URL
+	c.RetryPolicy = &fastRetry
+	// a valid token so that the poll is the first request to fail
+	c.Token = &Token{AccessToken: "test-token", ExpiredAt: time.Now().Add(time.Hour).Unix()}
+	w := c.NewWatcher()
+	w.Add(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"}, "old-md5")
+	changed, err := w.Poll()
+	if assert.NoError(t, err) {
+		assert.Len(t, changed, 1)
+	}
+	assert.Equal(t, ts.URL, c.URL)
+}
+
+func TestWatcherWatch(t *testing.T) {
+	ts, c := startWatchServer(t)
+	defer ts.Close()
+	w := c.NewWatcher()
+	w.Add(&GetCfgOpts{DataID: "test", Group: "DEFAULT_GROUP", NamespaceID: "test-tenant"}, "old-md5")
+	stop := errors.New("stop")
+	var got *Configuration
+	err := w.Watch(func(cfg *Configuration) error {
+		got = cfg
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	if assert.NotNil(t, got) {
+		assert.Equal(t, "test content", got.Content)
+	}
+	assert.Equal(t, "test-md5", w.items[0].md5)
+}
+
+func TestWatcherHTTPClient(t *testing.T) {
+	c := &Client{}
+	w := c.NewWatcher()
+	assert.Same(t, http.DefaultClient, w.httpClient())
+	c.httpClient = &http.Client{Timeout: 5 * time.Second}
+	assert.Equal(t, w.Timeout+pollMargin, w.httpClient().Timeout)
+	assert.Equal(t, 5*time.Second, c.HTTPClient().Timeout)
+	c.httpClient = &http.Client{Timeout: time.Minute}
+	assert.Same(t, c.httpClient, w.httpClient())
+}
+
+func TestParseChangedConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []*GetCfgOpts
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"no tenant", url.QueryEscape("a\x02g\x01"), []*GetCfgOpts{{DataID: "a", Group: "g"}}, false},
+		{"multiple", url.QueryEscape("a\x02g\x02ns\x01b\x02g\x01"), []*GetCfgOpts{{DataID: "a", Group: "g", NamespaceID: "ns"}, {DataID: "b", Group: "g"}}, false},
+		{"invalid", url.QueryEscape("a\x01"), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChangedConfigs(tt.data)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestContentMd5(t *testing.T) {
+	assert.Equal(t, "test-md5", ContentMd5(&Configuration{Content: "a", Md5: "test-md5"}))
+	assert.Equal(t, "0cc175b9c0f1b6a831c399e269772661", ContentMd5(&Configuration{Content: "a"}))
+	assert.Equal(t, "", ContentMd5(&Configuration{}))
+}